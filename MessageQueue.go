@@ -1,7 +1,9 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"math/rand"
 	"time"
 
@@ -15,16 +17,21 @@ const (
 )
 
 type telegramMessage struct {
-	ChatId        int64     `json:"chatId"`
-	MessageId     int       `json:"messageId"`
-	Message       string    `json:"message"`
-	Type          string    `json:"type"`
-	ImgUrl        string    `json:"imgUrl"`
-	Caption       string    `json:"caption"`
-	RetryCount    int       `json:"callbackCount"` //重试计数
-	CallbackRaw   string    `json:"-"`             //回调原串
-	RetryInterval int       `json:"retryInterval"` //重试间隔
-	NextTime      time.Time `json:"nextTime"`      //下一次重试的时间
+	ChatId        int64           `json:"chatId"`
+	MessageId     int             `json:"messageId"`
+	Message       string          `json:"message"`
+	Type          string          `json:"type"`
+	ImgUrl        string          `json:"imgUrl"`
+	Caption       string          `json:"caption"`
+	FileUrl       string          `json:"fileUrl,omitempty"` //文档、音频、视频等媒体的 URL 或 file_id
+	Payload       json.RawMessage `json:"payload,omitempty"` //位置、地点、联系人、投票等结构化内容
+	Options       *sendOptions    `json:"options,omitempty"`
+	RetryCount    int             `json:"callbackCount"` //重试计数
+	CallbackRaw   string          `json:"-"`             //回调原串
+	RetryInterval int             `json:"retryInterval"` //重试间隔
+	NextTime      time.Time       `json:"nextTime"`      //下一次重试的时间
+
+	sendProgress //拆分发送的进度，重试时不重复发送已发送的部分
 }
 
 type messageQueue struct {
@@ -33,14 +40,29 @@ type messageQueue struct {
 	size   int
 }
 
-func (mq *messageQueue) start() {
+func (mq *messageQueue) start(ctx context.Context) {
 
-	botLog.Println("[telegram_send_message] telegram message queue start。。。。。。。。。")
+	mq.client.log.Info("[telegram_send_message] telegram message queue start")
 	// 创建一个容量为max的goroutine池
 	p, _ := ants.NewPoolWithFunc(mq.size, func(i interface{}) {
 		cb := i.(*telegramMessage)
-		// 失败则放入重试队列
-		if err := mq.sendMessage(cb); err == nil {
+		// 失败则放入重试队列，429 不在 worker 内等待
+		err := mq.sendMessage(withSendProgress(withoutFloodWait(ctx), &cb.sendProgress), cb)
+		// 修改后内容没有变化说明目标状态已达成，不再重试
+		if err == nil || errors.Is(err, ErrNotModified) {
+			return
+		}
+
+		// 队列已停止，放回不计重试次数，已发送部分内容时需保存进度
+		if ctx.Err() != nil {
+			_ = mq.putRetryCache(cb, cb.CallbackRaw, cb.SentParts > 0)
+			return
+		}
+
+		// 触发限流，按 Telegram 返回的 retry_after 重新排期，不计重试次数
+		if wait := RetryAfter(err); wait > 0 {
+			cb.NextTime = time.Now().Add(wait)
+			_ = mq.putRetryCache(cb, cb.CallbackRaw, true)
 			return
 		}
 
@@ -57,41 +79,127 @@ func (mq *messageQueue) start() {
 	}, ants.WithPreAlloc(true), ants.WithNonblocking(false))
 	defer p.Release()
 
-	for {
+	for ctx.Err() == nil {
 
 		jsonStr, cmdErr := mq.store.BLPop()
-		if cmdErr != nil {
-			time.Sleep(time.Second * 3)
+		if cmdErr != nil || jsonStr == "" {
+			sleepContext(ctx, time.Second*3)
 			continue
 		}
-		if jsonStr == "" {
-			time.Sleep(time.Second * 3)
-			continue
+
+		// BLPop 期间队列已停止，放回消息后退出
+		if ctx.Err() != nil {
+			_ = mq.store.RPush(jsonStr)
+			break
 		}
 
 		var cb telegramMessage
 		if err := json.Unmarshal([]byte(jsonStr), &cb); err != nil {
-			botLog.Printf("[telegram_send_message] json Unmarshal, origin : %s ,err :%v \n", jsonStr, err)
+			if mq.client.redactText {
+				jsonStr = redactedValue
+			}
+			mq.client.log.Error("[telegram_send_message] json Unmarshal", "origin", jsonStr, "error", err)
 			continue
 		}
 
 		if !time.Now().After(cb.NextTime) {
 			_ = mq.putRetryCache(&cb, jsonStr, false)
-			time.Sleep(time.Duration(rand.Intn(3)+1) * time.Second)
+			sleepContext(ctx, time.Duration(rand.Intn(3)+1)*time.Second)
 			continue
 		}
 
 		cb.CallbackRaw, cb.RetryInterval = jsonStr, RetryInterval
 		_ = p.Invoke(&cb)
 	}
+	mq.client.log.Info("[telegram_send_message] telegram message queue stop")
 }
 
-func (mq *messageQueue) sendMessage(cb *telegramMessage) error {
+func (mq *messageQueue) sendMessage(ctx context.Context, cb *telegramMessage) error {
+	opts := cb.Options.asOptions()
 	switch cb.Type {
 	case MessageTypeText:
-		return mq.client.sendMessage(cb.ChatId, cb.MessageId, cb.Message)
+		_, err := mq.client.sendMessage(ctx, cb.ChatId, cb.MessageId, cb.Message, opts...)
+		return err
 	case MessageTypePhoto:
-		return mq.client.sendPhoto(cb.ChatId, cb.ImgUrl, cb.Caption)
+		_, err := mq.client.sendPhoto(ctx, cb.ChatId, FileFromURL(cb.ImgUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeDocument:
+		_, err := mq.client.sendDocument(ctx, cb.ChatId, FileFromURL(cb.FileUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeAudio:
+		_, err := mq.client.sendAudio(ctx, cb.ChatId, FileFromURL(cb.FileUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeVideo:
+		_, err := mq.client.sendVideo(ctx, cb.ChatId, FileFromURL(cb.FileUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeVoice:
+		_, err := mq.client.sendVoice(ctx, cb.ChatId, FileFromURL(cb.FileUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeAnimation:
+		_, err := mq.client.sendAnimation(ctx, cb.ChatId, FileFromURL(cb.FileUrl), cb.Caption, opts...)
+		return err
+	case MessageTypeSticker:
+		_, err := mq.client.sendSticker(ctx, cb.ChatId, FileFromURL(cb.FileUrl), opts...)
+		return err
+	case MessageTypeLocation:
+		var location Location
+		if err := json.Unmarshal(cb.Payload, &location); err != nil {
+			return err
+		}
+		_, err := mq.client.sendLocation(ctx, cb.ChatId, location, opts...)
+		return err
+	case MessageTypeVenue:
+		var venue Venue
+		if err := json.Unmarshal(cb.Payload, &venue); err != nil {
+			return err
+		}
+		_, err := mq.client.sendVenue(ctx, cb.ChatId, venue, opts...)
+		return err
+	case MessageTypeContact:
+		var contact Contact
+		if err := json.Unmarshal(cb.Payload, &contact); err != nil {
+			return err
+		}
+		_, err := mq.client.sendContact(ctx, cb.ChatId, contact, opts...)
+		return err
+	case MessageTypePoll:
+		var poll InputPoll
+		if err := json.Unmarshal(cb.Payload, &poll); err != nil {
+			return err
+		}
+		_, err := mq.client.sendPoll(ctx, cb.ChatId, poll, opts...)
+		return err
+	case MessageTypeDice:
+		_, err := mq.client.sendDice(ctx, cb.ChatId, cb.Message, opts...)
+		return err
+	case MessageTypeEditText:
+		_, err := mq.client.editMessageText(ctx, cb.ChatId, cb.MessageId, cb.Message, opts...)
+		return err
+	case MessageTypeEditCaption:
+		_, err := mq.client.editMessageCaption(ctx, cb.ChatId, cb.MessageId, cb.Caption, opts...)
+		return err
+	case MessageTypeEditReplyMarkup:
+		var markup *InlineKeyboardMarkup
+		if err := json.Unmarshal(cb.Payload, &markup); err != nil {
+			return err
+		}
+		_, err := mq.client.editMessageReplyMarkup(ctx, cb.ChatId, cb.MessageId, markup)
+		return err
+	case MessageTypeEditMedia:
+		var media InputMedia
+		if err := json.Unmarshal(cb.Payload, &media); err != nil {
+			return err
+		}
+		_, err := mq.client.editMessageMedia(ctx, cb.ChatId, cb.MessageId, media, opts...)
+		return err
+	case MessageTypeDelete:
+		return mq.client.deleteMessage(ctx, cb.ChatId, cb.MessageId)
+	case MessageTypeDeleteMany:
+		var messageIds []int
+		if err := json.Unmarshal(cb.Payload, &messageIds); err != nil {
+			return err
+		}
+		return mq.client.deleteMessages(ctx, cb.ChatId, messageIds)
 	default:
 		return NewError(MessageTypeError)
 	}
@@ -107,3 +215,15 @@ func (mq *messageQueue) putRetryCache(cb *telegramMessage, raw string, isTimeNow
 	}
 	return mq.store.RPush(string(cbByte))
 }
+
+// sleepContext 等待 d 或 ctx 结束，ctx 结束时返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
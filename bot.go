@@ -0,0 +1,266 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Bot is the synchronous client of a registered bot. Unlike the Push* functions,
+// every call waits for Telegram's answer and returns the result or an *APIError.
+type Bot struct {
+	client *botClient
+}
+
+// GetBot returns the synchronous client of the bot registered under alias
+func GetBot(alias string) (*Bot, error) {
+	bot := newBotUsing(alias)
+	if bot == nil || bot.client == nil {
+		return nil, NewError(TelegramBotError)
+	}
+	return &Bot{client: bot.client}, nil
+}
+
+// SendMessage sends a text message, replying to replyTo when it is positive
+func (b *Bot) SendMessage(ctx context.Context, chatID int64, replyTo int, text string, opts ...SendOption) (*Message, error) {
+	return b.client.sendMessage(ctx, chatID, replyTo, text, opts...)
+}
+
+// SendPhoto sends a photo with an optional caption
+func (b *Bot) SendPhoto(ctx context.Context, chatID int64, photo *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendPhoto(ctx, chatID, photo, caption, opts...)
+}
+
+// SendDocument sends a general file with an optional caption
+func (b *Bot) SendDocument(ctx context.Context, chatID int64, document *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendDocument(ctx, chatID, document, caption, opts...)
+}
+
+// SendAudio sends an audio file to be shown in the music player
+func (b *Bot) SendAudio(ctx context.Context, chatID int64, audio *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendAudio(ctx, chatID, audio, caption, opts...)
+}
+
+// SendVideo sends a video file
+func (b *Bot) SendVideo(ctx context.Context, chatID int64, video *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendVideo(ctx, chatID, video, caption, opts...)
+}
+
+// SendVoice sends a voice note
+func (b *Bot) SendVoice(ctx context.Context, chatID int64, voice *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendVoice(ctx, chatID, voice, caption, opts...)
+}
+
+// SendAnimation sends a GIF or a video without sound
+func (b *Bot) SendAnimation(ctx context.Context, chatID int64, animation *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.sendAnimation(ctx, chatID, animation, caption, opts...)
+}
+
+// SendSticker sends a sticker
+func (b *Bot) SendSticker(ctx context.Context, chatID int64, sticker *InputFile, opts ...SendOption) (*Message, error) {
+	return b.client.sendSticker(ctx, chatID, sticker, opts...)
+}
+
+// SendLocation sends a point on the map
+func (b *Bot) SendLocation(ctx context.Context, chatID int64, location Location, opts ...SendOption) (*Message, error) {
+	return b.client.sendLocation(ctx, chatID, location, opts...)
+}
+
+// SendVenue sends information about a venue
+func (b *Bot) SendVenue(ctx context.Context, chatID int64, venue Venue, opts ...SendOption) (*Message, error) {
+	return b.client.sendVenue(ctx, chatID, venue, opts...)
+}
+
+// SendContact sends a phone contact
+func (b *Bot) SendContact(ctx context.Context, chatID int64, contact Contact, opts ...SendOption) (*Message, error) {
+	return b.client.sendContact(ctx, chatID, contact, opts...)
+}
+
+// SendPoll sends a poll or a quiz
+func (b *Bot) SendPoll(ctx context.Context, chatID int64, poll InputPoll, opts ...SendOption) (*Message, error) {
+	return b.client.sendPoll(ctx, chatID, poll, opts...)
+}
+
+// SendDice sends an animated emoji with a random value
+func (b *Bot) SendDice(ctx context.Context, chatID int64, emoji string, opts ...SendOption) (*Message, error) {
+	return b.client.sendDice(ctx, chatID, emoji, opts...)
+}
+
+// ForwardMessage forwards a message from one chat to another
+func (b *Bot) ForwardMessage(ctx context.Context, chatID, fromChatID int64, messageID int, opts ...SendOption) (*Message, error) {
+	return b.client.forwardMessage(ctx, chatID, fromChatID, messageID, opts...)
+}
+
+// CopyMessage copies a message without a link to the original
+func (b *Bot) CopyMessage(ctx context.Context, chatID, fromChatID int64, messageID int, opts ...SendOption) (*MessageID, error) {
+	return b.client.copyMessage(ctx, chatID, fromChatID, messageID, opts...)
+}
+
+// SendMediaGroup sends a group of photos, videos, documents or audios as an album
+func (b *Bot) SendMediaGroup(ctx context.Context, chatID int64, media []InputMedia, opts ...SendOption) ([]Message, error) {
+	return b.client.sendMediaGroup(ctx, chatID, media, opts...)
+}
+
+// GetFile gets information about a file prepared for downloading
+func (b *Bot) GetFile(ctx context.Context, fileID string) (*File, error) {
+	return b.client.getFile(ctx, fileID)
+}
+
+// GetFileURL returns the download URL of file, or its absolute path in local mode
+func (b *Bot) GetFileURL(file *File) string {
+	return b.client.getFileURL(file)
+}
+
+// SetWebhook sets the webhook URL for the bot
+func (b *Bot) SetWebhook(ctx context.Context, url string, opts ...WebhookOption) error {
+	return b.client.setWebhook(ctx, url, opts...)
+}
+
+// DeleteWebhook removes the webhook integration, dropPendingUpdates also discards queued updates
+func (b *Bot) DeleteWebhook(ctx context.Context, dropPendingUpdates bool) error {
+	return b.client.deleteWebhook(ctx, dropPendingUpdates)
+}
+
+// GetWebhookInfo gets current webhook status
+func (b *Bot) GetWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	return b.client.getWebhookInfo(ctx)
+}
+
+// EditMessageText edits the text of a message sent by the bot
+func (b *Bot) EditMessageText(ctx context.Context, chatID int64, messageID int, text string, opts ...SendOption) (*Message, error) {
+	return b.client.editMessageText(ctx, chatID, messageID, text, opts...)
+}
+
+// EditMessageCaption edits the caption of a media message sent by the bot
+func (b *Bot) EditMessageCaption(ctx context.Context, chatID int64, messageID int, caption string, opts ...SendOption) (*Message, error) {
+	return b.client.editMessageCaption(ctx, chatID, messageID, caption, opts...)
+}
+
+// EditMessageMedia replaces the media of a message sent by the bot
+func (b *Bot) EditMessageMedia(ctx context.Context, chatID int64, messageID int, media InputMedia, opts ...SendOption) (*Message, error) {
+	return b.client.editMessageMedia(ctx, chatID, messageID, media, opts...)
+}
+
+// EditMessageReplyMarkup replaces the inline keyboard of a message sent by the bot
+func (b *Bot) EditMessageReplyMarkup(ctx context.Context, chatID int64, messageID int, markup *InlineKeyboardMarkup) (*Message, error) {
+	return b.client.editMessageReplyMarkup(ctx, chatID, messageID, markup)
+}
+
+// DeleteMessage deletes a message
+func (b *Bot) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	return b.client.deleteMessage(ctx, chatID, messageID)
+}
+
+// DeleteMessages deletes several messages of a chat
+func (b *Bot) DeleteMessages(ctx context.Context, chatID int64, messageIDs []int) error {
+	return b.client.deleteMessages(ctx, chatID, messageIDs)
+}
+
+// SendFormatted sends a FormattedText as plain text with entities, so nothing needs escaping
+func (b *Bot) SendFormatted(ctx context.Context, chatID int64, replyTo int, text FormattedText, opts ...SendOption) (*Message, error) {
+	plain, entities := text.Entities()
+	return b.client.sendMessage(ctx, chatID, replyTo, plain, append(opts, WithEntities(entities))...)
+}
+
+// BanChatMember bans a user until the given time, a zero until bans forever.
+// Errors caused by missing administrator rights match ErrNotEnoughRights, banning the owner or an administrator matches ErrTargetIsAdmin.
+func (b *Bot) BanChatMember(ctx context.Context, chatID, userID int64, until time.Time, revokeMessages bool) error {
+	return b.client.banChatMember(ctx, chatID, userID, until, revokeMessages)
+}
+
+// UnbanChatMember unbans a previously banned user
+func (b *Bot) UnbanChatMember(ctx context.Context, chatID, userID int64, onlyIfBanned bool) error {
+	return b.client.unbanChatMember(ctx, chatID, userID, onlyIfBanned)
+}
+
+// RestrictChatMember restricts a user until the given time, a zero until restricts forever
+func (b *Bot) RestrictChatMember(ctx context.Context, chatID, userID int64, permissions ChatPermissions, until time.Time) error {
+	return b.client.restrictChatMember(ctx, chatID, userID, permissions, until)
+}
+
+// PromoteChatMember promotes or demotes a user
+func (b *Bot) PromoteChatMember(ctx context.Context, chatID, userID int64, rights ChatAdministratorRights) error {
+	return b.client.promoteChatMember(ctx, chatID, userID, rights)
+}
+
+// SetChatPermissions sets the default permissions of all members
+func (b *Bot) SetChatPermissions(ctx context.Context, chatID int64, permissions ChatPermissions) error {
+	return b.client.setChatPermissions(ctx, chatID, permissions)
+}
+
+// SetChatTitle changes the title of a chat
+func (b *Bot) SetChatTitle(ctx context.Context, chatID int64, title string) error {
+	return b.client.setChatTitle(ctx, chatID, title)
+}
+
+// LeaveChat makes the bot leave a chat
+func (b *Bot) LeaveChat(ctx context.Context, chatID int64) error {
+	return b.client.leaveChat(ctx, chatID)
+}
+
+// GetMe returns basic information about the bot
+func (b *Bot) GetMe(ctx context.Context) (*User, error) {
+	return b.client.getMe(ctx)
+}
+
+// GetChat returns information about a chat
+func (b *Bot) GetChat(ctx context.Context, chatID int64) (*Chat, error) {
+	return b.client.getChat(ctx, chatID)
+}
+
+// GetChatMember returns information about a member of a chat
+func (b *Bot) GetChatMember(ctx context.Context, chatID, userID int64) (*ChatMember, error) {
+	return b.client.getChatMember(ctx, chatID, userID)
+}
+
+// GetChatAdministrators returns the administrators of a chat
+func (b *Bot) GetChatAdministrators(ctx context.Context, chatID int64) ([]ChatMember, error) {
+	return b.client.getChatAdministrators(ctx, chatID)
+}
+
+// GetChatMemberCount returns the number of members in a chat
+func (b *Bot) GetChatMemberCount(ctx context.Context, chatID int64) (int, error) {
+	return b.client.getChatMemberCount(ctx, chatID)
+}
+
+// SyncCommands publishes the commands registered with a description to the command menu,
+// updating only the scopes and languages that changed
+func (b *Bot) SyncCommands(ctx context.Context) error {
+	return b.client.syncCommands(ctx)
+}
+
+// GetMyCommands returns the commands shown for a scope and language, empty language for all languages
+func (b *Bot) GetMyCommands(ctx context.Context, scope BotCommandScope, language string) ([]BotCommand, error) {
+	return b.client.getMyCommands(ctx, scope, language)
+}
+
+// SetMyCommands replaces the commands shown for a scope and language
+func (b *Bot) SetMyCommands(ctx context.Context, commands []BotCommand, scope BotCommandScope, language string) error {
+	return b.client.setMyCommands(ctx, commands, scope, language)
+}
+
+// DeleteMyCommands removes the commands of a scope and language
+func (b *Bot) DeleteMyCommands(ctx context.Context, scope BotCommandScope, language string) error {
+	return b.client.deleteMyCommands(ctx, scope, language)
+}
+
+// Download writes the file identified by fileID to w
+func (b *Bot) Download(ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption) (*File, error) {
+	return b.client.download(ctx, fileID, w, opts...)
+}
+
+// DownloadMessageMedia writes the photo (largest size), animation, document, voice, audio, video,
+// video note or sticker attached to msg to w
+func (b *Bot) DownloadMessageMedia(ctx context.Context, msg *Message, w io.Writer, opts ...DownloadOption) (*File, error) {
+	return b.client.downloadMessageMedia(ctx, msg, w, opts...)
+}
+
+// AnswerCallbackQuery answers a pressed inline button, handlers registered with OnCallback are answered automatically
+func (b *Bot) AnswerCallbackQuery(ctx context.Context, queryID string, answer CallbackAnswer) error {
+	return b.client.answerCallbackQuery(ctx, queryID, answer)
+}
+
+// AnswerInlineQuery sends results for an inline query
+func (b *Bot) AnswerInlineQuery(ctx context.Context, queryID string, results []InlineQueryResult, answer InlineAnswer) error {
+	return b.client.answerInlineQuery(ctx, queryID, results, answer)
+}
@@ -2,20 +2,75 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	// defaultRequestTimeout bounds a Bot API call whose context carries no deadline
+	defaultRequestTimeout = 30 * time.Second
+	// defaultPollTimeout is the long-poll timeout sent with getUpdates
+	defaultPollTimeout = 50 * time.Second
+	// maxFloodRetries is how many times a call is retried after a 429 response
+	maxFloodRetries = 3
+
+	// DefaultAPIEndpoint is the public Bot API server
+	DefaultAPIEndpoint = "https://api.telegram.org"
+	// DefaultFileEndpoint serves files of the public Bot API server
+	DefaultFileEndpoint = "https://api.telegram.org/file"
+)
+
+type noFloodWaitKey struct{}
+
+type sendProgressKey struct{}
+
+// sendProgress 记录拆分发送的进度。队列重试时从第一个未发送的部分继续，已发送的部分不会重复发送
+type sendProgress struct {
+	SentParts int `json:"sentParts,omitempty"` // 已发送的部分数
+	ReplyTo   int `json:"replyTo,omitempty"`   // 下一部分回复的消息，即最后发送的部分
+}
+
+// withSendProgress 让 sendMessage 与带说明文字的媒体消息从 progress 记录的位置继续发送并更新进度
+func withSendProgress(ctx context.Context, progress *sendProgress) context.Context {
+	return context.WithValue(ctx, sendProgressKey{}, progress)
+}
+
+// progressFrom 返回 ctx 中的发送进度，没有时返回新的进度
+func progressFrom(ctx context.Context) *sendProgress {
+	if progress, ok := ctx.Value(sendProgressKey{}).(*sendProgress); ok && progress != nil {
+		return progress
+	}
+	return &sendProgress{}
+}
+
 // botClient represents a Telegram bot client
 type botClient struct {
-	token   string
-	baseURL string
-	parse   *commandParser
-	client  *http.Client
+	token        string
+	apiEndpoint  string
+	fileEndpoint string
+	parse        *commandParser
+	client       *http.Client
+	timeout      time.Duration
+	pollTimeout  time.Duration
+	log          Logger
+	redactText   bool
+	limiter      *rateLimiter
+	interceptors []Interceptor
+	cache        *ttlCache
+
+	allowedUpdates []string
+	webhookSecret  string
+
+	dispatchOnce   sync.Once
+	dispatchQueues []chan updateJob // webhook 更新按聊天分配到固定的 worker
 }
 
 type clientOptions func(*botClient) error
@@ -23,312 +78,317 @@ type clientOptions func(*botClient) error
 func withToken(token string) clientOptions {
 	return func(b *botClient) error {
 		b.token = token
-		b.baseURL = fmt.Sprintf("https://api.telegram.org/bot%s", token)
 		return nil
 	}
 }
 
-func withHook(webhook string) clientOptions {
+// withEndpoint 指定 Bot API 与文件下载地址，用于自建 telegram-bot-api 服务或测试
+func withEndpoint(apiEndpoint, fileEndpoint string) clientOptions {
 	return func(b *botClient) error {
-		if err := b.setWebhook(webhook); err != nil {
-			log.Printf("telegram机器人初始化失败，errpr:%+v", err)
-			return err
+		if apiEndpoint != "" {
+			b.apiEndpoint = strings.TrimRight(apiEndpoint, "/")
+		}
+		if fileEndpoint != "" {
+			b.fileEndpoint = strings.TrimRight(fileEndpoint, "/")
+		} else if apiEndpoint != "" {
+			b.fileEndpoint = b.apiEndpoint + "/file"
 		}
 		return nil
 	}
 }
 
-func withParse(parse *commandParser) clientOptions {
+// withWebhookSecret 设置 Webhook 请求头 X-Telegram-Bot-Api-Secret-Token 的期望值
+func withWebhookSecret(secret string) clientOptions {
 	return func(b *botClient) error {
-		b.parse = parse
+		b.webhookSecret = secret
 		return nil
 	}
 }
 
-func newBotWidthOptions(ops ...clientOptions) (*botClient, error) {
-
-	options := &botClient{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
-
-	for _, op := range ops {
-		if err := op(options); err != nil {
-			botLog.Printf("telegram机器人初始化失败，errpr:%v \n", err)
-			return nil, err
+func withHook(ctx context.Context, webhook string, opts ...WebhookOption) clientOptions {
+	return func(b *botClient) error {
+		if err := b.setWebhook(ctx, webhook, opts...); err != nil {
+			return err
 		}
+		return nil
 	}
-
-	return options, nil
 }
 
-func newBotClient(token, webhook string) *botClient {
-	ops := []clientOptions{
-		withToken(token),
-		withParse(newCommandParser("/")),
-	}
-	if webhook != "" {
-		ops = append(ops, withHook(webhook))
+// withLogger 设置机器人日志，输出前自动隐藏 token 与 secrets；redactText 为 true 时同时隐藏消息正文
+func withLogger(logger Logger, redactText bool, secrets ...string) clientOptions {
+	return func(b *botClient) error {
+		b.log = newRedactLogger(logger, b.token, secrets...)
+		b.redactText = redactText
+		return nil
 	}
+}
 
-	bot, err := newBotWidthOptions(ops...)
-	if err != nil {
+// withRateLimit 设置发送限流，直接发送与消息队列共用同一个限流器
+func withRateLimit(config RateLimitConfig) clientOptions {
+	return func(b *botClient) error {
+		b.limiter = newRateLimiter(config)
 		return nil
 	}
-	return bot
 }
 
-// SendMessage sends a message to a chat
-func (b *botClient) sendMessage(chatID int64, messageId int, text string) error {
-
-	params := map[string]interface{}{
-		"chat_id": chatID,
-		"text":    text,
+// withTransport 设置访问 Bot API 使用的 http.Client，支持代理、TLS 与自定义 RoundTripper
+func withTransport(config TransportConfig) clientOptions {
+	return func(b *botClient) error {
+		client, err := newHTTPClient(config)
+		if err != nil {
+			return err
+		}
+		b.client = client
+		return nil
 	}
-	if messageId > 0 {
-		params["reply_parameters"] = map[string]interface{}{
-			"message_id": messageId,
+}
+
+// withTimeout 设置请求的兜底超时与 getUpdates 长轮询超时，零值保持默认
+func withTimeout(request, poll time.Duration) clientOptions {
+	return func(b *botClient) error {
+		if request > 0 {
+			b.timeout = request
 		}
+		if poll > 0 {
+			b.pollTimeout = poll
+		}
+		return nil
 	}
-	respBody, err := b.doRequest("sendMessage", params)
-	if err != nil {
-		return err
+}
+
+// withAllowedUpdates 设置 getUpdates 接收的更新类型，为空时保持上次的设置
+func withAllowedUpdates(updates []string) clientOptions {
+	return func(b *botClient) error {
+		b.allowedUpdates = updates
+		return nil
 	}
+}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
+// withInterceptors 追加请求拦截器，先注册的位于外层
+func withInterceptors(interceptors ...Interceptor) clientOptions {
+	return func(b *botClient) error {
+		b.interceptors = append(b.interceptors, interceptors...)
+		return nil
 	}
+}
 
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
+// withCache 设置 getMe、getChat 等查询结果的缓存时间，负数禁用缓存
+func withCache(ttl time.Duration) clientOptions {
+	return func(b *botClient) error {
+		b.cache = newTTLCache(ttl)
+		return nil
 	}
+}
 
-	return nil
+func withParse(parse *commandParser) clientOptions {
+	return func(b *botClient) error {
+		b.parse = parse
+		return nil
+	}
 }
 
-// ReplyMessage replies to a message
-func (b *botClient) replyMessage(chatId int64, messageId int, text string) error {
+func newBotWidthOptions(ops ...clientOptions) (*botClient, error) {
 
-	params := map[string]interface{}{
-		"chat_id": chatId,
-		"text":    text,
-		"reply_parameters": map[string]interface{}{
-			"message_id": messageId,
-		},
-	}
-	respBody, err := b.doRequest("sendMessage", params)
-	if err != nil {
-		return err
+	options := &botClient{
+		apiEndpoint:  DefaultAPIEndpoint,
+		fileEndpoint: DefaultFileEndpoint,
+		client:       &http.Client{},
+		timeout:      defaultRequestTimeout,
+		pollTimeout:  defaultPollTimeout,
+		log:          botLog,
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
+	for _, op := range ops {
+		if err := op(options); err != nil {
+			options.log.Error("telegram机器人初始化失败", "error", err)
+			return nil, err
+		}
 	}
 
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
+	return options, nil
+}
+
+func newBotClient(ctx context.Context, config *Config) (*botClient, error) {
+	ops := []clientOptions{
+		withToken(config.Token),
+		withEndpoint(config.APIEndpoint, config.FileEndpoint),
+		withLogger(config.Logger, config.RedactText, config.WebhookSecret),
+		withRateLimit(config.RateLimit),
+		withTransport(config.Transport),
+		withTimeout(config.RequestTimeout, config.PollTimeout),
+		withInterceptors(config.Interceptors...),
+		withCache(config.CacheTTL),
+		withAllowedUpdates(config.AllowedUpdates),
+		withWebhookSecret(config.WebhookSecret),
+		withParse(newCommandParser("/")),
 	}
-	return nil
+	if config.Webhook != "" {
+		ops = append(ops, withHook(ctx, config.Webhook, config.webhookOptions()...))
+	}
+
+	return newBotWidthOptions(ops...)
 }
 
-// SendPhoto sends a photo to a chat
-func (b *botClient) sendPhoto(chatID int64, photoURL, caption string) error {
+// SendMessage sends a message to a chat. Texts longer than MaxMessageLength are split
+// on paragraph, line or word boundaries and sent in order as a reply chain; the first
+// part is returned.
+func (b *botClient) sendMessage(ctx context.Context, chatID int64, messageId int, text string, opts ...SendOption) (*Message, error) {
 
-	params := map[string]interface{}{
-		"chat_id": chatID,
-		"photo":   photoURL,
+	options := newSendOptions(opts)
+	chunks := splitText(text, options.entities(), options.parseMode(), MaxMessageLength)
+	if len(chunks) == 0 {
+		chunks = []textChunk{{text: text, entities: options.entities()}}
 	}
 
-	if caption != "" {
-		params["caption"] = caption
-	}
-	respBody, err := b.doRequest("sendPhoto", params)
-	if err != nil {
-		return err
+	// 重试时跳过已发送的部分，从上次中断的位置继续回复链
+	progress := progressFrom(ctx)
+	if progress.SentParts > 0 && progress.ReplyTo > 0 {
+		messageId = progress.ReplyTo
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
-	}
+	var first *Message
+	for i := progress.SentParts; i < len(chunks); i++ {
+		chunk := chunks[i]
+		params := map[string]interface{}{
+			"chat_id": chatID,
+			"text":    chunk.text,
+		}
+		if messageId > 0 {
+			params["reply_parameters"] = map[string]interface{}{
+				"message_id": messageId,
+			}
+		}
+		options.forChunk(chunk, i == len(chunks)-1).apply(params)
 
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
+		message, err := callAPI[*Message](ctx, b, "sendMessage", params)
+		if err != nil {
+			return first, err
+		}
+		if first == nil {
+			first = message
+		}
+		progress.SentParts = i + 1
+		if message != nil {
+			messageId = message.MessageID
+			progress.ReplyTo = messageId
+		}
 	}
-	return nil
+	return first, nil
+}
+
+// ReplyMessage replies to a message
+func (b *botClient) replyMessage(ctx context.Context, chatId int64, messageId int, text string, opts ...SendOption) (*Message, error) {
+	return b.sendMessage(ctx, chatId, messageId, text, opts...)
+}
+
+// SendPhoto sends a photo to a chat. A caption longer than MaxCaptionLength is cut at a
+// safe boundary and the remainder follows as a text message replying to the photo.
+func (b *botClient) sendPhoto(ctx context.Context, chatID int64, photo *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendPhoto", "photo", chatID, photo, caption, opts...)
 }
 
 // ForwardMessage forwards a message from one chat to another
-func (b *botClient) forwardMessage(chatID, fromChatID int64, messageID int) error {
+func (b *botClient) forwardMessage(ctx context.Context, chatID, fromChatID int64, messageID int, opts ...SendOption) (*Message, error) {
 
 	params := map[string]interface{}{
 		"chat_id":      chatID,
 		"from_chat_id": fromChatID,
 		"message_id":   messageID,
 	}
-	respBody, err := b.doRequest("forwardMessage", params)
-	if err != nil {
-		return err
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
-	}
-
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
-	}
-	return nil
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "forwardMessage", params)
 }
 
-// CopyMessage copies a message from one chat to another
-func (b *botClient) copyMessage(chatID, fromChatID int64, messageID int) error {
+// CopyMessage copies a message from one chat to another, returning the id of the copy
+func (b *botClient) copyMessage(ctx context.Context, chatID, fromChatID int64, messageID int, opts ...SendOption) (*MessageID, error) {
 
 	params := map[string]interface{}{
 		"chat_id":      chatID,
 		"from_chat_id": fromChatID,
 		"message_id":   messageID,
 	}
-	respBody, err := b.doRequest("copyMessage", params)
-	if err != nil {
-		return err
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
-	}
-
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
-	}
-	return nil
+	newSendOptions(opts).apply(params)
+	return callAPI[*MessageID](ctx, b, "copyMessage", params)
 }
 
 // GetFile gets information about a file by its file_id
-func (b *botClient) getFile(fileID string) (*File, error) {
+func (b *botClient) getFile(ctx context.Context, fileID string) (*File, error) {
 
 	params := map[string]interface{}{
 		"file_id": fileID,
 	}
-	respBody, err := b.doRequest("getFile", params)
-	if err != nil {
-		return nil, err
-	}
-
-	var result struct {
-		Ok     bool `json:"ok"`
-		Result File `json:"result"`
-	}
-
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return nil, fmt.Errorf("failed to parse response: %v", err)
-	}
-
-	if !result.Ok {
-		botLog.Printf("telegram API error: %v \n", result.Result)
-		return nil, fmt.Errorf("telegram API error: %v", result.Result)
-	}
-
-	return &result.Result, nil
+	return callAPI[*File](ctx, b, "getFile", params)
 }
 
-// GetFileURL returns the full URL to download a file
+// GetFileURL returns the full URL to download a file, or the absolute path when
+// a self-hosted Bot API server in local mode already stores the file on disk
 func (b *botClient) getFileURL(file *File) string {
-	return fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", b.token, file.FilePath)
+	if file.IsLocal() {
+		return file.FilePath
+	}
+	return fmt.Sprintf("%s/bot%s/%s", b.fileEndpoint, b.token, file.FilePath)
 }
 
 // SendMediaGroup sends a group of photos as an album
-func (b *botClient) sendMediaGroup(chatID int64, media []InputMedia) error {
+func (b *botClient) sendMediaGroup(ctx context.Context, chatID int64, media []InputMedia, opts ...SendOption) ([]Message, error) {
 
 	params := map[string]interface{}{
 		"chat_id": chatID,
 		"media":   media,
 	}
-	respBody, err := b.doRequest("sendMediaGroup", params)
-	if err != nil {
-		return err
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
-	}
-
-	if !result["ok"].(bool) {
-		botLog.Printf("telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
-	}
-	return nil
+	newSendOptions(opts).apply(params)
+	return callAPI[[]Message](ctx, b, "sendMediaGroup", params)
 }
 
 // GetUpdates retrieves updates from the bot
-func (b *botClient) getUpdates(offset int64, limit int) ([]Update, error) {
+func (b *botClient) getUpdates(ctx context.Context, offset int64, limit int) ([]Update, error) {
 
 	params := map[string]interface{}{
-		"offset": offset,
-		"limit":  limit,
-	}
-	respBody, err := b.doRequest("getUpdates", params)
-	if err != nil {
-		return nil, err
+		"offset":  offset,
+		"limit":   limit,
+		"timeout": int(b.pollTimeout / time.Second),
 	}
-
-	var result struct {
-		Ok     bool     `json:"ok"`
-		Result []Update `json:"result"`
+	if len(b.allowedUpdates) > 0 {
+		params["allowed_updates"] = b.allowedUpdates
 	}
 
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("failed to parse response: %+v \n", err)
-		return nil, fmt.Errorf("failed to parse response: %v", err)
+	// 长轮询需要在 pollTimeout 之外再留出一次普通请求的时间
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.pollTimeout+b.timeout)
+		defer cancel()
 	}
+	return callAPI[[]Update](ctx, b, "getUpdates", params)
+}
 
-	if !result.Ok {
-		botLog.Printf("telegram API error: %v \n", result.Result)
-		return nil, fmt.Errorf("telegram API error")
+// handleUpdate 处理一条来自长轮询或 Webhook 的更新并记录错误，处理程序 panic 不影响后续更新
+func (b *botClient) handleUpdate(ctx context.Context, update *Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("[TelegramBot.Update] handler panic", "update_id", update.UpdateID, "panic", r)
+		}
+	}()
+	if err := b.processUpdate(ctx, update); err != nil && !isCommandNotFound(err) {
+		b.log.Warn("[TelegramBot.Update] failed to process update", "update_id", update.UpdateID, "error", err)
 	}
+}
 
-	return result.Result, nil
+// isCommandNotFound 判断是否为非命令文本导致的错误，这类更新无需记录
+func isCommandNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == CommandNotFoundError
 }
 
-func (b *botClient) processUpdate(update *Update) error {
-	// Only process message updates
-	if update.Message == nil {
-		return nil
-	}
+func (b *botClient) processUpdate(ctx context.Context, update *Update) error {
+	// 成员变化后缓存的成员与管理员信息失效
+	b.invalidateMember(update.ChatMember)
+	b.invalidateMember(update.MyChatMember)
 
-	// Only process text messages
-	if update.Message.Text == "" {
-		return nil
-	}
-
-	// Parse command from message
-	command := b.parse.ParseCommand(update.Message.Text, update.Message)
-	if command == nil {
-		return NewError(CommandNotFoundError)
-	}
-	return command.Handler()
+	return b.routeUpdate(ctx, update)
 }
 
 // ProcessMessage 处理消息并执行相应的命令处理程序
-func (b *botClient) processMessage(message *Message) error {
+func (b *botClient) processMessage(ctx context.Context, message *Message) error {
 	// Only process text messages
 	if message.Text == "" && len(message.Photo) == 0 {
 		return nil
@@ -340,136 +400,225 @@ func (b *botClient) processMessage(message *Message) error {
 	}
 
 	// Parse command from message
-	command := b.parse.ParseCommand(commandText, message)
-	if command == nil {
+	command := b.parse.ParseCommand(ctx, commandText, message)
+	if command == nil || b.addressedToOtherBot(ctx, command) {
 		return NewError(CommandNotFoundError)
 	}
+	command.client = b
 
 	return command.Handler()
 }
 
-// SetWebhook sets the webhook URL for the bot
-func (b *botClient) setWebhook(url string) error {
+// SetWebhook sets the webhook URL for the bot, opts set the secret token, allowed updates,
+// connection limit, IP address, certificate and whether pending updates are dropped
+func (b *botClient) setWebhook(ctx context.Context, url string, opts ...WebhookOption) error {
 
 	params := map[string]interface{}{
 		"url": url,
 	}
-	respBody, err := b.doRequest("setWebhook", params)
-	if err != nil {
-		botLog.Printf("[TelegramBot.SetWebhook] 设置webhook异常：  err : %v \n", err)
+	for _, opt := range opts {
+		opt(params)
+	}
+	if _, err := callAPI[bool](ctx, b, "setWebhook", params); err != nil {
+		b.log.Error("[TelegramBot.SetWebhook] 设置webhook异常", "error", err)
 		return err
 	}
+	return nil
+}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("[TelegramBot.SetWebhook] failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
-	}
+// DeleteWebhook removes the webhook integration, dropPendingUpdates also discards queued updates
+func (b *botClient) deleteWebhook(ctx context.Context, dropPendingUpdates bool) error {
 
-	if !result["ok"].(bool) {
-		botLog.Printf("[TelegramBot.SetWebhook] telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
+	params := map[string]interface{}{}
+	if dropPendingUpdates {
+		params["drop_pending_updates"] = true
+	}
+	if _, err := callAPI[bool](ctx, b, "deleteWebhook", params); err != nil {
+		b.log.Error("[TelegramBot.DeleteWebhook] 删除webhook异常", "error", err)
+		return err
 	}
-
 	return nil
 }
 
-// DeleteWebhook removes the webhook integration
-func (b *botClient) deleteWebhook() error {
-	reqUrl := fmt.Sprintf("%s/deleteWebhook", b.baseURL)
+// GetWebhookInfo gets current webhook status
+func (b *botClient) getWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	return callAPI[*WebhookInfo](ctx, b, "getWebhookInfo", map[string]interface{}{})
+}
 
-	resp, err := b.client.Get(reqUrl)
-	if err != nil {
-		return fmt.Errorf("failed to delete webhook: %v", err)
-	}
-	defer resp.Body.Close()
+// callAPI 调用 Bot API 方法并将响应信封中的 result 解码为 T
+func callAPI[T any](ctx context.Context, b *botClient, method string, params map[string]interface{}) (T, error) {
+	var result T
 
-	respBody, err := io.ReadAll(resp.Body)
+	raw, err := b.invoke(ctx, method, params)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return result, err
 	}
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("[TelegramBot.DeleteWebhook] failed to parse response: %+v \n", err)
-		return NewError(ParseResponseError)
+	if len(raw) == 0 {
+		return result, nil
 	}
-
-	if !result["ok"].(bool) {
-		botLog.Printf("[TelegramBot.DeleteWebhook] telegram API error: %v \n", result["description"])
-		return NewError(TelegramApiError)
+	if err := json.Unmarshal(raw, &result); err != nil {
+		b.log.Error("[TelegramBot.Request] failed to parse result", "method", method, "error", err)
+		return result, NewError(ParseResponseError)
 	}
-	return nil
+	return result, nil
 }
 
-// GetWebhookInfo gets current webhook status
-func (b *botClient) getWebhookInfo() (map[string]interface{}, error) {
-
-	url := fmt.Sprintf("%s/getWebhookInfo", b.baseURL)
+// invoke 依次经过拦截器链后执行请求
+func (b *botClient) invoke(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	return chainInterceptors(b.interceptors, b.execute)(ctx, method, params)
+}
 
-	resp, err := b.client.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get webhook info: %v", err)
+// execute 执行限流与请求，遇到 429 时按 Telegram 返回的 retry_after 等待后透明重试
+func (b *botClient) execute(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	for attempt := 0; ; attempt++ {
+		if err := b.limiter.wait(ctx, method, params); err != nil {
+			return nil, err
+		}
+		result, err := b.executeOnce(ctx, method, params)
+		wait, ok := floodWait(ctx, err, attempt)
+		// 不可 seek 的 reader 已被读完，无法重新上传，由调用方处理 429
+		if !ok || !uploadsReplayable(params) {
+			return result, err
+		}
+		b.log.Warn("[TelegramBot.Request] flood control", "method", method, "retry_after", wait)
+		if !sleepContext(ctx, wait) {
+			return result, err
+		}
 	}
-	defer resp.Body.Close()
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+func (b *botClient) executeOnce(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+	var result apiResponse[json.RawMessage]
+
+	begin := time.Now()
+	body, reqErr := b.doRequest(ctx, method, params)
+	fields := []any{"method", method, "duration", time.Since(begin)}
+	if chatID, ok := params["chat_id"]; ok {
+		fields = append(fields, "chat_id", chatID)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	if reqErr != nil && len(body) == 0 {
+		b.log.Error("[TelegramBot.Request] request failed", append(fields, "error", reqErr)...)
+		return nil, reqErr
 	}
 
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		botLog.Printf("[TelegramBot.GetWebhookInfo] failed to parse response: %+v \n", err)
+	if err := json.Unmarshal(body, &result); err != nil {
+		if reqErr != nil {
+			b.log.Error("[TelegramBot.Request] request failed", append(fields, "error", reqErr)...)
+			return nil, reqErr
+		}
+		b.log.Error("[TelegramBot.Request] failed to parse response", append(fields, "error", err)...)
 		return nil, NewError(ParseResponseError)
 	}
 
-	if !result["ok"].(bool) {
-		botLog.Printf("[TelegramBot.GetWebhookInfo] telegram API error: %v \n", result["description"])
-		return nil, NewError(TelegramApiError)
+	if !result.Ok {
+		b.log.Warn("[TelegramBot.Request] telegram API error",
+			append(fields, "error_code", result.ErrorCode, "description", result.Description)...)
+		return nil, &APIError{
+			Method:      method,
+			Code:        result.ErrorCode,
+			Description: result.Description,
+			Parameters:  result.Parameters,
+		}
 	}
-	return result["result"].(map[string]interface{}), nil
+	b.log.Debug("[TelegramBot.Request] request done", fields...)
+	return result.Result, nil
 }
 
-func (b *botClient) doRequest(api string, params map[string]interface{}) (body []byte, err error) {
+func (b *botClient) doRequest(ctx context.Context, api string, params map[string]interface{}) (body []byte, err error) {
+
+	ctx, cancel := b.requestContext(ctx)
+	defer cancel()
 
-	reqUrl := fmt.Sprintf("%s/%s", b.baseURL, api)
-	botLog.Printf("[TelegramBot.Request] 请求地址：%s \n", reqUrl)
+	reqUrl := fmt.Sprintf("%s/bot%s/%s", b.apiEndpoint, b.token, api)
 
-	paramBytes, _ := json.Marshal(params)
-	botLog.Printf("[TelegramBot.Request] 请求参数：%s", string(paramBytes))
+	// 包含本地文件或 reader 时使用 multipart/form-data 上传
+	var reqBody io.Reader
+	contentType := "application/json"
+	uploads, form := collectUploads(params)
+	if len(uploads) > 0 {
+		var multipartBody io.ReadCloser
+		if multipartBody, contentType, err = encodeMultipart(form, uploads); err != nil {
+			return nil, err
+		}
+		reqBody = multipartBody
+	}
+
+	paramBytes, _ := json.Marshal(form)
+	if reqBody == nil {
+		reqBody = bytes.NewReader(paramBytes)
+	}
+	logBytes, _ := json.Marshal(redactParams(form, b.redactText))
+	b.log.Debug("[TelegramBot.Request] request", "method", api, "params", string(logBytes))
 
-	req, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(paramBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, reqBody)
 	if err != nil {
-		botLog.Printf("[TelegramBot.Request] 创建请求异常：  err : %v \n", err)
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		// 关闭管道，结束写入 multipart 的 goroutine
+		if closer, ok := reqBody.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("failed to create request: %v", unwrapURLError(err))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := b.client.Do(req)
 	if err != nil {
-		botLog.Printf("[TelegramBot.Request] 发送请求异常：  err : %v \n", err)
-		return nil, fmt.Errorf("failed to send message: %v", err)
+		// url.Error 中的请求地址包含 token，不向外暴露
+		return nil, fmt.Errorf("failed to send message: %w", unwrapURLError(err))
 	}
 	defer resp.Body.Close()
 
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if b.redactText {
+		b.log.Debug("[TelegramBot.Request] response", "method", api, "status", resp.StatusCode, "size", len(body))
+	} else {
+		b.log.Debug("[TelegramBot.Request] response", "method", api, "status", resp.StatusCode, "body", string(body))
+	}
+
+	// 非 200 响应仍返回响应体，由调用方解析 error_code 与 description
 	if resp.StatusCode != http.StatusOK {
-		botLog.Printf("[TelegramBot.Request] request failed with status %d \n", resp.StatusCode)
-		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return body, fmt.Errorf("request failed with status %d", resp.StatusCode)
 	}
+	return body, nil
+}
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		botLog.Printf("[TelegramBot.Request] 读取响应异常：  err : %v \n", err)
+// unwrapURLError 去掉 url.Error 外层，避免错误信息中带出包含 token 的请求地址
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// floodWait 判断 err 是否为可等待重试的 429 错误，返回需要等待的时长
+func floodWait(ctx context.Context, err error, attempt int) (time.Duration, bool) {
+	wait := RetryAfter(err)
+	if wait <= 0 || attempt >= maxFloodRetries {
+		return 0, false
+	}
+	if skip, _ := ctx.Value(noFloodWaitKey{}).(bool); skip {
+		return 0, false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return 0, false
+	}
+	return wait, true
+}
+
+// withoutFloodWait 让请求在 429 时立即返回错误而不在客户端内等待，由调用方自行安排重试
+func withoutFloodWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noFloodWaitKey{}, true)
+}
+
+// requestContext applies the client's fallback timeout when ctx has no deadline of its own
+func (b *botClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || b.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	botLog.Printf("[TelegramBot.Request] 响应参数：%s", string(body))
-	return
+	return context.WithTimeout(ctx, b.timeout)
 }
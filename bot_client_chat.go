@@ -0,0 +1,194 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// BanChatMember bans a user until the given time, a zero until bans forever.
+// revokeMessages also deletes all messages the user sent to the chat.
+func (b *botClient) banChatMember(ctx context.Context, chatID, userID int64, until time.Time, revokeMessages bool) error {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+	if !until.IsZero() {
+		params["until_date"] = until.Unix()
+	}
+	if revokeMessages {
+		params["revoke_messages"] = true
+	}
+	_, err := callAPI[bool](ctx, b, "banChatMember", params)
+	b.invalidateAfter(err, chatID, userID)
+	return err
+}
+
+// UnbanChatMember unbans a previously banned user, onlyIfBanned avoids removing a current member
+func (b *botClient) unbanChatMember(ctx context.Context, chatID, userID int64, onlyIfBanned bool) error {
+
+	params := map[string]interface{}{
+		"chat_id":        chatID,
+		"user_id":        userID,
+		"only_if_banned": onlyIfBanned,
+	}
+	_, err := callAPI[bool](ctx, b, "unbanChatMember", params)
+	b.invalidateAfter(err, chatID, userID)
+	return err
+}
+
+// RestrictChatMember restricts a user until the given time, a zero until restricts forever
+func (b *botClient) restrictChatMember(ctx context.Context, chatID, userID int64, permissions ChatPermissions, until time.Time) error {
+
+	params := map[string]interface{}{
+		"chat_id":     chatID,
+		"user_id":     userID,
+		"permissions": permissions,
+	}
+	if permissions.independent() {
+		params["use_independent_chat_permissions"] = true
+	}
+	if !until.IsZero() {
+		params["until_date"] = until.Unix()
+	}
+	_, err := callAPI[bool](ctx, b, "restrictChatMember", params)
+	b.invalidateAfter(err, chatID, userID)
+	return err
+}
+
+// PromoteChatMember promotes or demotes a user, all false rights demote the user
+func (b *botClient) promoteChatMember(ctx context.Context, chatID, userID int64, rights ChatAdministratorRights) error {
+
+	params := map[string]interface{}{}
+	raw, _ := json.Marshal(rights)
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return err
+	}
+	params["chat_id"] = chatID
+	params["user_id"] = userID
+	_, err := callAPI[bool](ctx, b, "promoteChatMember", params)
+	b.invalidateAfter(err, chatID, userID)
+	return err
+}
+
+// SetChatPermissions sets the default permissions of all members
+func (b *botClient) setChatPermissions(ctx context.Context, chatID int64, permissions ChatPermissions) error {
+
+	params := map[string]interface{}{
+		"chat_id":     chatID,
+		"permissions": permissions,
+	}
+	if permissions.independent() {
+		params["use_independent_chat_permissions"] = true
+	}
+	_, err := callAPI[bool](ctx, b, "setChatPermissions", params)
+	b.invalidateAfter(err, chatID, 0)
+	return err
+}
+
+// SetChatTitle changes the title of a chat
+func (b *botClient) setChatTitle(ctx context.Context, chatID int64, title string) error {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"title":   title,
+	}
+	_, err := callAPI[bool](ctx, b, "setChatTitle", params)
+	b.invalidateAfter(err, chatID, 0)
+	return err
+}
+
+// LeaveChat makes the bot leave a group, supergroup or channel
+func (b *botClient) leaveChat(ctx context.Context, chatID int64) error {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+	}
+	_, err := callAPI[bool](ctx, b, "leaveChat", params)
+	b.invalidateAfter(err, chatID, 0)
+	return err
+}
+
+// GetMe returns basic information about the bot, cached
+func (b *botClient) getMe(ctx context.Context) (*User, error) {
+	user, err := cached(b.cache, "me", func() (User, error) {
+		user, err := callAPI[*User](ctx, b, "getMe", map[string]interface{}{})
+		if err != nil || user == nil {
+			return User{}, err
+		}
+		return *user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetChat returns up to date information about a chat, cached
+func (b *botClient) getChat(ctx context.Context, chatID int64) (*Chat, error) {
+	chat, err := cached(b.cache, chatCacheKey("chat", chatID), func() (Chat, error) {
+		chat, err := callAPI[*Chat](ctx, b, "getChat", map[string]interface{}{"chat_id": chatID})
+		if err != nil || chat == nil {
+			return Chat{}, err
+		}
+		return *chat, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// GetChatMember returns information about a member of a chat, cached
+func (b *botClient) getChatMember(ctx context.Context, chatID, userID int64) (*ChatMember, error) {
+	member, err := cached(b.cache, memberCacheKey(chatID, userID), func() (ChatMember, error) {
+		params := map[string]interface{}{
+			"chat_id": chatID,
+			"user_id": userID,
+		}
+		member, err := callAPI[*ChatMember](ctx, b, "getChatMember", params)
+		if err != nil || member == nil {
+			return ChatMember{}, err
+		}
+		return *member, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetChatAdministrators returns the administrators of a chat other than bots, cached
+func (b *botClient) getChatAdministrators(ctx context.Context, chatID int64) ([]ChatMember, error) {
+	admins, err := cached(b.cache, chatCacheKey("admins", chatID), func() ([]ChatMember, error) {
+		return callAPI[[]ChatMember](ctx, b, "getChatAdministrators", map[string]interface{}{"chat_id": chatID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]ChatMember(nil), admins...), nil
+}
+
+// GetChatMemberCount returns the number of members in a chat, cached
+func (b *botClient) getChatMemberCount(ctx context.Context, chatID int64) (int, error) {
+	return cached(b.cache, chatCacheKey("count", chatID), func() (int, error) {
+		return callAPI[int](ctx, b, "getChatMemberCount", map[string]interface{}{"chat_id": chatID})
+	})
+}
+
+// invalidateAfter 在修改群组或成员的调用成功后清除相关缓存，userID 为 0 时清除整个群组的缓存。
+// 默认的 allowed_updates 不包含 chat_member，机器人自身的修改不能依赖更新来失效缓存
+func (b *botClient) invalidateAfter(err error, chatID, userID int64) {
+	if err == nil {
+		b.cache.invalidateChat(chatID, userID)
+	}
+}
+
+// invalidateMember 在收到 chat_member / my_chat_member 更新后清除相关缓存
+func (b *botClient) invalidateMember(update *ChatMemberUpdated) {
+	if update == nil {
+		return
+	}
+	b.cache.invalidateChat(update.Chat.ID, update.NewChatMember.User.ID)
+}
@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// menuCommandName 是 Telegram 命令菜单允许的命令名
+var menuCommandName = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// commandTarget 是一组命令所属的范围与语言，语言为空表示所有语言
+type commandTarget struct {
+	scope    BotCommandScope
+	language string
+}
+
+// GetMyCommands returns the commands shown for the given scope and language
+func (b *botClient) getMyCommands(ctx context.Context, scope BotCommandScope, language string) ([]BotCommand, error) {
+
+	params := map[string]interface{}{
+		"scope": scope,
+	}
+	if language != "" {
+		params["language_code"] = language
+	}
+	return callAPI[[]BotCommand](ctx, b, "getMyCommands", params)
+}
+
+// SetMyCommands replaces the commands shown for the given scope and language
+func (b *botClient) setMyCommands(ctx context.Context, commands []BotCommand, scope BotCommandScope, language string) error {
+
+	params := map[string]interface{}{
+		"commands": commands,
+		"scope":    scope,
+	}
+	if language != "" {
+		params["language_code"] = language
+	}
+	_, err := callAPI[bool](ctx, b, "setMyCommands", params)
+	return err
+}
+
+// DeleteMyCommands removes the commands of the given scope and language, clients fall back to a broader scope
+func (b *botClient) deleteMyCommands(ctx context.Context, scope BotCommandScope, language string) error {
+
+	params := map[string]interface{}{
+		"scope": scope,
+	}
+	if language != "" {
+		params["language_code"] = language
+	}
+	_, err := callAPI[bool](ctx, b, "deleteMyCommands", params)
+	return err
+}
+
+// syncCommands 将已注册且带描述的命令同步到命令菜单：
+// 按范围与语言与 getMyCommands 的结果比较，有差异时 setMyCommands，不再需要的通用范围 deleteMyCommands
+func (b *botClient) syncCommands(ctx context.Context) error {
+	desired := registeredMenuCommands(b.log)
+
+	// 通用范围在每种已使用的语言下都检查一遍，以清除已取消注册的命令
+	languages := map[string]bool{"": true}
+	for target := range desired {
+		languages[target.language] = true
+	}
+	targets := make(map[commandTarget]bool, len(desired))
+	for target := range desired {
+		targets[target] = true
+	}
+	for _, scope := range []BotCommandScope{ScopeDefault(), ScopeAllPrivateChats(), ScopeAllGroupChats(), ScopeAllChatAdministrators()} {
+		for language := range languages {
+			targets[commandTarget{scope: scope, language: language}] = true
+		}
+	}
+
+	for _, target := range sortedTargets(targets) {
+		current, err := b.getMyCommands(ctx, target.scope, target.language)
+		if err != nil {
+			return err
+		}
+		want := desired[target]
+		if slices.Equal(current, want) {
+			continue
+		}
+
+		if len(want) == 0 {
+			err = b.deleteMyCommands(ctx, target.scope, target.language)
+		} else {
+			err = b.setMyCommands(ctx, want, target.scope, target.language)
+		}
+		if err != nil {
+			return err
+		}
+		b.log.Info("bot commands synced", "scope", target.scope.Type, "chat_id", target.scope.ChatID,
+			"language", target.language, "count", len(want))
+	}
+	return nil
+}
+
+// registeredMenuCommands 按范围与语言整理已注册的命令，命令按名称排序。
+// 某个语言的列表会替换默认列表，因此没有该语言翻译的命令使用默认描述补齐
+func registeredMenuCommands(log Logger) map[commandTarget][]BotCommand {
+	names := make([]string, 0, len(commandMetas))
+	for name := range commandMetas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type menuEntry struct {
+		name  string
+		scope BotCommandScope
+		meta  *commandMeta
+	}
+	var entries []menuEntry
+	languages := make(map[BotCommandScope]map[string]bool)
+	for _, name := range names {
+		meta := commandMetas[name]
+		if meta.description == "" && len(meta.translations) == 0 {
+			continue
+		}
+		if !menuCommandName.MatchString(name) {
+			log.Warn("command not shown in menu, name must be 1-32 lowercase letters, digits or underscores", "command", name)
+			continue
+		}
+
+		scopes := meta.scopes
+		if len(scopes) == 0 {
+			scopes = []BotCommandScope{ScopeDefault()}
+		}
+		for _, scope := range scopes {
+			entries = append(entries, menuEntry{name: name, scope: scope, meta: meta})
+			for language := range meta.translations {
+				if languages[scope] == nil {
+					languages[scope] = make(map[string]bool)
+				}
+				languages[scope][language] = true
+			}
+		}
+	}
+
+	desired := make(map[commandTarget][]BotCommand)
+	for _, entry := range entries {
+		if entry.meta.description != "" {
+			target := commandTarget{scope: entry.scope}
+			desired[target] = append(desired[target], BotCommand{Command: entry.name, Description: entry.meta.description})
+		}
+		for language := range languages[entry.scope] {
+			description := entry.meta.translations[language]
+			if description == "" {
+				description = entry.meta.description
+			}
+			if description == "" {
+				continue
+			}
+			target := commandTarget{scope: entry.scope, language: language}
+			desired[target] = append(desired[target], BotCommand{Command: entry.name, Description: description})
+		}
+	}
+	return desired
+}
+
+// sortedTargets 返回固定顺序的范围列表，使同步过程可预期
+func sortedTargets(targets map[commandTarget]bool) []commandTarget {
+	sorted := make([]commandTarget, 0, len(targets))
+	for target := range targets {
+		sorted = append(sorted, target)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.scope.Type != b.scope.Type {
+			return a.scope.Type < b.scope.Type
+		}
+		if a.scope.ChatID != b.scope.ChatID {
+			return a.scope.ChatID < b.scope.ChatID
+		}
+		if a.scope.UserID != b.scope.UserID {
+			return a.scope.UserID < b.scope.UserID
+		}
+		return a.language < b.language
+	})
+	return sorted
+}
@@ -0,0 +1,213 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultMaxDownloadSize is the largest file the cloud Bot API lets bots download
+	DefaultMaxDownloadSize = 20 << 20
+
+	maxDownloadRetries   = 3
+	downloadRetryBackoff = 500 * time.Millisecond
+)
+
+type downloadOptions struct {
+	maxSize int64
+}
+
+// DownloadOption configures a file download
+type DownloadOption func(*downloadOptions)
+
+// WithMaxSize rejects files larger than size bytes with ErrFileTooLarge, 0 disables the check.
+// The default is DefaultMaxDownloadSize, raise it for a self-hosted Bot API server.
+func WithMaxSize(size int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.maxSize = size
+	}
+}
+
+// Download writes the file to w and returns its metadata. Transient network and server
+// errors are retried as long as nothing has been written to w yet.
+func (b *botClient) download(ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption) (*File, error) {
+	options := downloadOptions{maxSize: DefaultMaxDownloadSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	file, err := b.getFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil || file.FilePath == "" {
+		return nil, NewError(IllegalParameterError, "file is not available for download")
+	}
+	if options.maxSize > 0 && int64(file.FileSize) > options.maxSize {
+		return file, ErrFileTooLarge
+	}
+
+	// 本地模式下文件已在磁盘上，直接读取
+	if file.IsLocal() {
+		f, err := os.Open(file.FilePath)
+		if err != nil {
+			return file, err
+		}
+		defer f.Close()
+		return file, copyLimited(w, f, options.maxSize)
+	}
+
+	counter := &countingWriter{w: w}
+	for attempt := 0; ; attempt++ {
+		retry, err := b.downloadOnce(ctx, file, counter, options.maxSize)
+		if err == nil || !retry || counter.n > 0 || attempt+1 >= maxDownloadRetries {
+			return file, err
+		}
+		b.log.Warn("[TelegramBot.Download] retrying", "file_id", fileID, "attempt", attempt+1, "error", err)
+		if !sleepContext(ctx, downloadRetryBackoff*time.Duration(attempt+1)) {
+			return file, ctx.Err()
+		}
+	}
+}
+
+// downloadOnce 下载一次文件，返回的 retry 表示错误是否可重试。
+// 文件大小不定，不限制总时长；等待响应或读取数据超过 RequestTimeout 没有进展时中止
+func (b *botClient) downloadOnce(ctx context.Context, file *File, w io.Writer, maxSize int64) (bool, error) {
+	reqCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	progress := func() {}
+	if b.timeout > 0 {
+		stalled := time.AfterFunc(b.timeout, func() {
+			cancel(fmt.Errorf("download stalled for %s", b.timeout))
+		})
+		defer stalled.Stop()
+		progress = func() { stalled.Reset(b.timeout) }
+	}
+	// 优先返回空闲超时的原因，而不是笼统的 context canceled
+	failure := func(err error) error {
+		if cause := context.Cause(reqCtx); cause != nil && ctx.Err() == nil {
+			return cause
+		}
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, b.getFileURL(file), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %v", unwrapURLError(err))
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		// url.Error 中的下载地址包含 token，不向外暴露
+		return ctx.Err() == nil, fmt.Errorf("failed to download file: %w", failure(unwrapURLError(err)))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return false, ErrFileTooLarge
+	}
+
+	if err := copyLimited(w, &progressReader{r: resp.Body, progress: progress}, maxSize); err != nil {
+		return !errors.Is(err, ErrFileTooLarge) && ctx.Err() == nil, failure(err)
+	}
+	return false, nil
+}
+
+// downloadMessageMedia 下载消息中的媒体，照片取最大的尺寸
+func (b *botClient) downloadMessageMedia(ctx context.Context, msg *Message, w io.Writer, opts ...DownloadOption) (*File, error) {
+	fileID := MediaFileID(msg)
+	if fileID == "" {
+		return nil, NewError(MessageTypeError, "message has no downloadable media")
+	}
+	return b.download(ctx, fileID, w, opts...)
+}
+
+// MediaFileID returns the file_id of the media attached to msg, the largest size for photos,
+// or an empty string when msg carries no photo, animation, document, voice, audio, video, video note or sticker
+func MediaFileID(msg *Message) string {
+	switch {
+	case msg == nil:
+		return ""
+	case len(msg.Photo) > 0:
+		return LargestPhoto(msg.Photo).FileID
+	case msg.Animation != nil:
+		return msg.Animation.FileID
+	case msg.Document != nil:
+		return msg.Document.FileID
+	case msg.Voice != nil:
+		return msg.Voice.FileID
+	case msg.Audio != nil:
+		return msg.Audio.FileID
+	case msg.Video != nil:
+		return msg.Video.FileID
+	case msg.VideoNote != nil:
+		return msg.VideoNote.FileID
+	case msg.Sticker != nil:
+		return msg.Sticker.FileID
+	}
+	return ""
+}
+
+// LargestPhoto returns the biggest of the sizes Telegram sends for a photo, nil for an empty slice
+func LargestPhoto(sizes []PhotoSize) *PhotoSize {
+	var largest *PhotoSize
+	for i := range sizes {
+		size := &sizes[i]
+		if largest == nil || size.Width*size.Height > largest.Width*largest.Height ||
+			(size.Width*size.Height == largest.Width*largest.Height && size.FileSize > largest.FileSize) {
+			largest = size
+		}
+	}
+	return largest
+}
+
+// copyLimited 复制 r 到 w，超过 maxSize 时返回 ErrFileTooLarge，maxSize 为 0 不限制
+func copyLimited(w io.Writer, r io.Reader, maxSize int64) error {
+	if maxSize <= 0 {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	if _, err := io.Copy(w, io.LimitReader(r, maxSize)); err != nil {
+		return err
+	}
+	// 读满上限后仍有数据说明文件过大
+	if n, _ := r.Read(make([]byte, 1)); n > 0 {
+		return ErrFileTooLarge
+	}
+	return nil
+}
+
+// countingWriter 记录已写入的字节数，写入过数据后不再重试
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// progressReader 每次读到数据时调用 progress，用于重置空闲超时
+type progressReader struct {
+	r        io.Reader
+	progress func()
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		pr.progress()
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"context"
+)
+
+// maxDeleteMessages is the most message ids deleteMessages accepts per call
+const maxDeleteMessages = 100
+
+// EditMessageText edits the text of a message sent by the bot
+func (b *botClient) editMessageText(ctx context.Context, chatID int64, messageID int, text string, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "editMessageText", params)
+}
+
+// EditMessageCaption edits the caption of a media message sent by the bot
+func (b *botClient) editMessageCaption(ctx context.Context, chatID int64, messageID int, caption string, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"caption":    caption,
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "editMessageCaption", params)
+}
+
+// EditMessageMedia replaces the photo, video, document or audio of a message sent by the bot
+func (b *botClient) editMessageMedia(ctx context.Context, chatID int64, messageID int, media InputMedia, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"media":      media,
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "editMessageMedia", params)
+}
+
+// EditMessageReplyMarkup replaces the inline keyboard of a message sent by the bot, a nil markup removes it
+func (b *botClient) editMessageReplyMarkup(ctx context.Context, chatID int64, messageID int, markup *InlineKeyboardMarkup) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+	if markup != nil {
+		params["reply_markup"] = markup
+	}
+	return callAPI[*Message](ctx, b, "editMessageReplyMarkup", params)
+}
+
+// DeleteMessage deletes a message
+func (b *botClient) deleteMessage(ctx context.Context, chatID int64, messageID int) error {
+
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+	_, err := callAPI[bool](ctx, b, "deleteMessage", params)
+	return err
+}
+
+// DeleteMessages deletes several messages of a chat, in batches of at most 100 ids
+func (b *botClient) deleteMessages(ctx context.Context, chatID int64, messageIDs []int) error {
+	for len(messageIDs) > 0 {
+		batch := messageIDs
+		if len(batch) > maxDeleteMessages {
+			batch = batch[:maxDeleteMessages]
+		}
+		messageIDs = messageIDs[len(batch):]
+
+		params := map[string]interface{}{
+			"chat_id":     chatID,
+			"message_ids": batch,
+		}
+		if _, err := callAPI[bool](ctx, b, "deleteMessages", params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package telegram
+
+import (
+	"context"
+)
+
+// sendMedia 发送带说明文字的媒体消息，field 为文件参数名。
+// 超过 MaxCaptionLength 的说明文字在安全位置截断，剩余部分作为回复该媒体的文本消息发送
+func (b *botClient) sendMedia(ctx context.Context, method, field string, chatID int64, file *InputFile, caption string, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		field:     file,
+	}
+
+	options := newSendOptions(opts)
+	head, overflow := splitHead(caption, options.entities(), options.parseMode(), MaxCaptionLength)
+	if head.text != "" {
+		params["caption"] = head.text
+	}
+	options.forChunk(head, overflow.text == "").apply(params)
+
+	// 第 0 部分为媒体本身，之后为说明文字超出的部分；重试时跳过已发送的部分
+	progress := progressFrom(ctx)
+	var message *Message
+	if progress.SentParts == 0 {
+		var err error
+		if message, err = callAPI[*Message](ctx, b, method, params); err != nil {
+			return message, err
+		}
+		progress.SentParts = 1
+		if message != nil {
+			progress.ReplyTo = message.MessageID
+		}
+	}
+	if overflow.text == "" {
+		return message, nil
+	}
+
+	textProgress := &sendProgress{SentParts: progress.SentParts - 1, ReplyTo: progress.ReplyTo}
+	defer func() {
+		progress.SentParts, progress.ReplyTo = textProgress.SentParts+1, textProgress.ReplyTo
+	}()
+	overflowOptions := options.forChunk(overflow, true)
+	if _, err := b.sendMessage(withSendProgress(ctx, textProgress), chatID, progress.ReplyTo, overflow.text, overflowOptions.asOptions()...); err != nil {
+		return message, err
+	}
+	return message, nil
+}
+
+// SendDocument sends a general file
+func (b *botClient) sendDocument(ctx context.Context, chatID int64, document *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendDocument", "document", chatID, document, caption, opts...)
+}
+
+// SendAudio sends an audio file to be shown in the music player
+func (b *botClient) sendAudio(ctx context.Context, chatID int64, audio *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendAudio", "audio", chatID, audio, caption, opts...)
+}
+
+// SendVideo sends a video file
+func (b *botClient) sendVideo(ctx context.Context, chatID int64, video *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendVideo", "video", chatID, video, caption, opts...)
+}
+
+// SendVoice sends an OGG/OPUS, MP3 or M4A file as a voice note
+func (b *botClient) sendVoice(ctx context.Context, chatID int64, voice *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendVoice", "voice", chatID, voice, caption, opts...)
+}
+
+// SendAnimation sends a GIF or H.264/MPEG-4 AVC video without sound
+func (b *botClient) sendAnimation(ctx context.Context, chatID int64, animation *InputFile, caption string, opts ...SendOption) (*Message, error) {
+	return b.sendMedia(ctx, "sendAnimation", "animation", chatID, animation, caption, opts...)
+}
+
+// SendSticker sends a static, animated or video sticker
+func (b *botClient) sendSticker(ctx context.Context, chatID int64, sticker *InputFile, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+		"sticker": sticker,
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendSticker", params)
+}
+
+// SendLocation sends a point on the map
+func (b *botClient) sendLocation(ctx context.Context, chatID int64, location Location, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":   chatID,
+		"latitude":  location.Latitude,
+		"longitude": location.Longitude,
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendLocation", params)
+}
+
+// SendVenue sends information about a venue
+func (b *botClient) sendVenue(ctx context.Context, chatID int64, venue Venue, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":   chatID,
+		"latitude":  venue.Location.Latitude,
+		"longitude": venue.Location.Longitude,
+		"title":     venue.Title,
+		"address":   venue.Address,
+	}
+	if venue.FoursquareID != "" {
+		params["foursquare_id"] = venue.FoursquareID
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendVenue", params)
+}
+
+// SendContact sends a phone contact
+func (b *botClient) sendContact(ctx context.Context, chatID int64, contact Contact, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id":      chatID,
+		"phone_number": contact.PhoneNumber,
+		"first_name":   contact.FirstName,
+	}
+	if contact.LastName != "" {
+		params["last_name"] = contact.LastName
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendContact", params)
+}
+
+// SendPoll sends a regular poll or a quiz
+func (b *botClient) sendPoll(ctx context.Context, chatID int64, poll InputPoll, opts ...SendOption) (*Message, error) {
+
+	options := make([]map[string]string, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		options = append(options, map[string]string{"text": option})
+	}
+	params := map[string]interface{}{
+		"chat_id":  chatID,
+		"question": poll.Question,
+		"options":  options,
+	}
+	if poll.IsAnonymous != nil {
+		params["is_anonymous"] = *poll.IsAnonymous
+	}
+	if poll.Type != "" {
+		params["type"] = poll.Type
+	}
+	if poll.AllowsMultipleAnswers {
+		params["allows_multiple_answers"] = true
+	}
+	// 测验必须指定正确选项，0 也是有效值
+	if poll.Type == PollTypeQuiz {
+		params["correct_option_id"] = poll.CorrectOptionID
+	}
+	if poll.Explanation != "" {
+		params["explanation"] = poll.Explanation
+	}
+	if poll.OpenPeriod > 0 {
+		params["open_period"] = poll.OpenPeriod
+	}
+	if poll.IsClosed {
+		params["is_closed"] = true
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendPoll", params)
+}
+
+// SendDice sends an animated emoji with a random value, an empty emoji sends DiceDice
+func (b *botClient) sendDice(ctx context.Context, chatID int64, emoji string, opts ...SendOption) (*Message, error) {
+
+	params := map[string]interface{}{
+		"chat_id": chatID,
+	}
+	if emoji != "" {
+		params["emoji"] = emoji
+	}
+	newSendOptions(opts).apply(params)
+	return callAPI[*Message](ctx, b, "sendDice", params)
+}
@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL is how long chat and member lookups are cached when Config.CacheTTL is zero
+const defaultCacheTTL = time.Minute
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// ttlCache 按机器人缓存 getMe、getChat 等查询结果，过期后重新请求
+type ttlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+// newTTLCache 创建缓存，ttl 为负数时禁用缓存
+func newTTLCache(ttl time.Duration) *ttlCache {
+	if ttl < 0 {
+		return nil
+	}
+	if ttl == 0 {
+		ttl = defaultCacheTTL
+	}
+	return &ttlCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func (c *ttlCache) get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache) set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// 写入时顺带清理过期项，避免长期运行后无限增长
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+// invalidateChat 删除与 chatID 相关的缓存；userID 不为 0 时只删除该成员的成员信息
+func (c *ttlCache) invalidateChat(chatID, userID int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chat := strconv.FormatInt(chatID, 10)
+	for key := range c.entries {
+		parts := strings.Split(key, ":")
+		if len(parts) < 2 || parts[1] != chat {
+			continue
+		}
+		if parts[0] == "member" && userID != 0 && parts[2] != strconv.FormatInt(userID, 10) {
+			continue
+		}
+		delete(c.entries, key)
+	}
+}
+
+// cached 返回缓存中的结果，未命中时调用 load 并缓存成功的结果
+func cached[T any](c *ttlCache, key string, load func() (T, error)) (T, error) {
+	if value, ok := c.get(key); ok {
+		return value.(T), nil
+	}
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.set(key, value)
+	return value, nil
+}
+
+func chatCacheKey(kind string, chatID int64) string {
+	return kind + ":" + strconv.FormatInt(chatID, 10)
+}
+
+func memberCacheKey(chatID, userID int64) string {
+	return chatCacheKey("member", chatID) + ":" + strconv.FormatInt(userID, 10)
+}
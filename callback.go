@@ -0,0 +1,204 @@
+package telegram
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// CallbackAnswer is shown to the user after pressing an inline button
+type CallbackAnswer struct {
+	Text      string // 0-200 个字符的提示
+	ShowAlert bool   // 以对话框而不是顶部通知显示 Text
+	URL       string // 打开的链接，如 t.me/your_bot?start=XXXX
+	CacheTime int    // 客户端缓存结果的秒数
+}
+
+// AnswerCallbackQuery stops the loading indicator of a pressed inline button
+func (b *botClient) answerCallbackQuery(ctx context.Context, queryID string, answer CallbackAnswer) error {
+
+	params := map[string]interface{}{
+		"callback_query_id": queryID,
+	}
+	if answer.Text != "" {
+		params["text"] = answer.Text
+	}
+	if answer.ShowAlert {
+		params["show_alert"] = true
+	}
+	if answer.URL != "" {
+		params["url"] = answer.URL
+	}
+	if answer.CacheTime > 0 {
+		params["cache_time"] = answer.CacheTime
+	}
+	_, err := callAPI[bool](ctx, b, "answerCallbackQuery", params)
+	return err
+}
+
+// CallbackHandlerFunc handles a pressed inline button
+type CallbackHandlerFunc func(callback *CallbackContext) error
+
+// CallbackContext is passed to callback handlers. The query is answered with the text,
+// alert or URL set through Answer, Alert and OpenURL once the handler returns.
+type CallbackContext struct {
+	*Command
+	Query *CallbackQuery
+	// Args 为前缀之后以 ":" 分隔的各段，或正则的各个分组
+	Args []string
+	// Params 为正则中命名分组的值
+	Params map[string]string
+
+	answer CallbackAnswer
+}
+
+// Answer shows text as a notification at the top of the chat
+func (c *CallbackContext) Answer(text string) {
+	c.answer.Text, c.answer.ShowAlert = text, false
+}
+
+// Alert shows text in a dialog the user has to dismiss
+func (c *CallbackContext) Alert(text string) {
+	c.answer.Text, c.answer.ShowAlert = text, true
+}
+
+// OpenURL makes the client open url, only game and t.me/bot?start= links are accepted
+func (c *CallbackContext) OpenURL(url string) {
+	c.answer.URL = url
+}
+
+// EditText edits the text of the message carrying the button, the returned message is nil for inline messages
+func (c *CallbackContext) EditText(text string, opts ...SendOption) (*Message, error) {
+	params := map[string]interface{}{
+		"text": text,
+	}
+	newSendOptions(opts).apply(params)
+	return c.editMessage("editMessageText", params)
+}
+
+// EditCaption edits the caption of the message carrying the button
+func (c *CallbackContext) EditCaption(caption string, opts ...SendOption) (*Message, error) {
+	params := map[string]interface{}{
+		"caption": caption,
+	}
+	newSendOptions(opts).apply(params)
+	return c.editMessage("editMessageCaption", params)
+}
+
+// EditReplyMarkup replaces the inline keyboard of the message carrying the button, nil removes it
+func (c *CallbackContext) EditReplyMarkup(markup *InlineKeyboardMarkup) (*Message, error) {
+	params := map[string]interface{}{}
+	if markup != nil {
+		params["reply_markup"] = markup
+	}
+	return c.editMessage("editMessageReplyMarkup", params)
+}
+
+// DeleteMessage deletes the message carrying the button
+func (c *CallbackContext) DeleteMessage() error {
+	if c.Query.Message == nil {
+		return NewError(IllegalParameterError, "inline messages cannot be deleted")
+	}
+	return c.client.deleteMessage(c.Context(), c.Query.Message.Chat.ID, c.Query.Message.MessageID)
+}
+
+// editMessage 修改按钮所在的消息，内联模式发送的消息使用 inline_message_id，成功时 Telegram 只返回 true
+func (c *CallbackContext) editMessage(method string, params map[string]interface{}) (*Message, error) {
+	if c.Query.Message != nil {
+		params["chat_id"] = c.Query.Message.Chat.ID
+		params["message_id"] = c.Query.Message.MessageID
+		return callAPI[*Message](c.Context(), c.client, method, params)
+	}
+	if c.Query.InlineMessageID == "" {
+		return nil, NewError(IllegalParameterError, "callback query has no message")
+	}
+	params["inline_message_id"] = c.Query.InlineMessageID
+	_, err := callAPI[bool](c.Context(), c.client, method, params)
+	return nil, err
+}
+
+// callbackRoute 按前缀或正则匹配回调数据
+type callbackRoute struct {
+	prefix  string
+	pattern *regexp.Regexp
+	handler CallbackHandlerFunc
+}
+
+// callbackRoutes 按注册顺序匹配，先注册的优先
+var callbackRoutes []callbackRoute
+
+// OnCallback 处理 data 等于 prefix 或以 prefix + ":" 开头的回调，其余各段放入 Args，
+// 如 OnCallback("vote", h) 匹配 "vote:42:up" 且 Args 为 ["42", "up"]
+func OnCallback(prefix string, handler CallbackHandlerFunc) {
+	callbackRoutes = append(callbackRoutes, callbackRoute{prefix: strings.TrimSuffix(prefix, ":"), handler: handler})
+}
+
+// OnCallbackPattern 处理匹配正则 pattern 的回调，分组放入 Args，命名分组同时放入 Params；
+// pattern 无效时 panic
+func OnCallbackPattern(pattern string, handler CallbackHandlerFunc) {
+	callbackRoutes = append(callbackRoutes, callbackRoute{pattern: regexp.MustCompile(pattern), handler: handler})
+}
+
+// match 判断 data 是否匹配，返回提取的参数
+func (r *callbackRoute) match(data string) ([]string, map[string]string, bool) {
+	if r.pattern == nil {
+		if data == r.prefix {
+			return nil, nil, true
+		}
+		if rest, ok := strings.CutPrefix(data, r.prefix+":"); ok {
+			return strings.Split(rest, ":"), nil, true
+		}
+		return nil, nil, false
+	}
+
+	groups := r.pattern.FindStringSubmatch(data)
+	if groups == nil {
+		return nil, nil, false
+	}
+	params := make(map[string]string)
+	for i, name := range r.pattern.SubexpNames() {
+		if name != "" {
+			params[name] = groups[i]
+		}
+	}
+	return groups[1:], params, true
+}
+
+// routeCallback 将回调交给第一个匹配的处理程序，处理完成后自动应答；
+// 没有匹配时交给 OnCallbackQuery 注册的处理程序，由其自行应答，两者都没有时直接应答
+func (b *botClient) routeCallback(ctx context.Context, update *Update) error {
+	query := update.CallbackQuery
+	command := &Command{
+		Kind:    UpdateCallbackQuery,
+		Update:  update,
+		Message: query.Message,
+		RawText: query.Data,
+		ctx:     ctx,
+		client:  b,
+	}
+
+	for i := range callbackRoutes {
+		route := &callbackRoutes[i]
+		if args, params, ok := route.match(query.Data); ok {
+			callback := &CallbackContext{Command: command, Query: query, Args: args, Params: params}
+			return b.runCallback(callback, route.handler)
+		}
+	}
+
+	if handler := updateHandlers[UpdateCallbackQuery]; handler != nil {
+		return command.run(handler)
+	}
+	return b.answerCallbackQuery(ctx, query.ID, CallbackAnswer{})
+}
+
+// runCallback 执行处理程序并应答回调。应答在 defer 中进行，处理失败或 panic 时按钮同样停止加载
+func (b *botClient) runCallback(callback *CallbackContext, handler CallbackHandlerFunc) (err error) {
+	defer func() {
+		if answerErr := b.answerCallbackQuery(callback.Context(), callback.Query.ID, callback.answer); err == nil {
+			err = answerErr
+		}
+	}()
+	return callback.run(func(*Command) error {
+		return handler(callback)
+	})
+}
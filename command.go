@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"strings"
 )
 
@@ -9,10 +10,43 @@ type Command struct {
 	Name      string
 	Arguments []string
 	RawText   string
-	Message   *Message
+	// Message 为触发命令的消息；回调、内联查询、成员变更等没有消息的更新中为 nil，
+	// 此时通过 Kind 判断更新类型并从 Update 中读取数据
+	Message *Message
+
+	// Kind 为更新类型，如 UpdateMessage、UpdateCallbackQuery；Update 为完整的更新，
+	// 通过 ProcessMessage 处理的消息没有 Update
+	Kind   string
+	Update *Update
+
+	ctx     context.Context
+	client  *botClient
+	mention string // 群组中 /cmd@BotName 形式的机器人用户名
+}
+
+// Context returns the context of the update that produced the command
+func (c *Command) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Bot returns the synchronous client of the bot that received the update
+func (c *Command) Bot() *Bot {
+	if c.client == nil {
+		return nil
+	}
+	return &Bot{client: c.client}
 }
 
 func (c *Command) Handler() error {
+	// Find and execute command handler
+	return c.run(commands[c.Name])
+}
+
+// run 依次执行中间件与 handler，handler 为空时只执行中间件
+func (c *Command) run(handler CommandHandlerFunc) error {
 	// Run middleware
 	for _, m := range middleware {
 		if err := m(c); err != nil {
@@ -20,11 +54,9 @@ func (c *Command) Handler() error {
 		}
 	}
 
-	// Find and execute command handler
-	if handler, exists := commands[c.Name]; exists {
+	if handler != nil {
 		return handler(c)
 	}
-
 	return nil
 }
 
@@ -32,16 +64,60 @@ func (c *Command) Handler() error {
 type CommandHandlerFunc func(command *Command) error
 
 var (
-	commands   = make(map[string]CommandHandlerFunc)
-	middleware = make([]CommandHandlerFunc, 0)
+	commands     = make(map[string]CommandHandlerFunc)
+	commandMetas = make(map[string]*commandMeta)
+	middleware   = make([]CommandHandlerFunc, 0)
 )
 
-// RegisterCommandFunc 为特定命令注册处理程序函数
-func RegisterCommandFunc(name string, handler CommandHandlerFunc) {
+// commandMeta 命令在 Telegram 命令菜单中的展示信息
+type commandMeta struct {
+	description  string
+	scopes       []BotCommandScope
+	translations map[string]string // 语言代码 -> 描述
+}
+
+// CommandOption configures how a registered command is shown in the command menu
+type CommandOption func(*commandMeta)
+
+// WithDescription shows the command in the command menu with description.
+// Commands without a description are handled but never synced to Telegram.
+func WithDescription(description string) CommandOption {
+	return func(m *commandMeta) {
+		m.description = description
+	}
+}
+
+// WithScope limits the command to the given scopes, ScopeDefault when none is set
+func WithScope(scopes ...BotCommandScope) CommandOption {
+	return func(m *commandMeta) {
+		m.scopes = append(m.scopes, scopes...)
+	}
+}
+
+// WithLanguage shows description to users whose client uses the given two-letter language code
+func WithLanguage(language, description string) CommandOption {
+	return func(m *commandMeta) {
+		if m.translations == nil {
+			m.translations = make(map[string]string)
+		}
+		m.translations[language] = description
+	}
+}
+
+// RegisterCommandFunc 为特定命令注册处理程序函数，opts 设置命令菜单中的描述、范围与语言
+func RegisterCommandFunc(name string, handler CommandHandlerFunc, opts ...CommandOption) {
 	commands[name] = handler
+
+	meta := &commandMeta{}
+	for _, opt := range opts {
+		opt(meta)
+	}
+	commandMetas[name] = meta
 }
 
-// Use 将中间件添加到命令解析器中
+// Use 将中间件添加到命令解析器中。中间件对所有路由到处理程序的更新执行，而不只是命令消息：
+// command.Message 可能为 nil（如 UpdateInlineQuery、UpdateChatMember），应先检查 command.Kind 或 Message；
+// 中间件返回错误时停止处理该更新
 func Use(m ...CommandHandlerFunc) {
 	middleware = append(middleware, m...)
 }
@@ -59,7 +135,7 @@ func newCommandParser(prefix string) *commandParser {
 }
 
 // ParseCommand 从消息文本中解析命令
-func (cp *commandParser) ParseCommand(text string, message *Message) *Command {
+func (cp *commandParser) ParseCommand(ctx context.Context, text string, message *Message) *Command {
 	if !strings.HasPrefix(text, cp.prefix) {
 		return nil
 	}
@@ -74,10 +150,19 @@ func (cp *commandParser) ParseCommand(text string, message *Message) *Command {
 		return nil
 	}
 
+	// 群组菜单中的命令带有 @机器人用户名，如 /start@my_bot
+	name, mention, _ := strings.Cut(strings.ToLower(parts[0]), "@")
+	if name == "" {
+		return nil
+	}
+
 	command := &Command{
-		Name:    strings.ToLower(parts[0]),
+		Name:    name,
+		mention: mention,
 		RawText: text,
 		Message: message,
+		Kind:    UpdateMessage,
+		ctx:     ctx,
 	}
 
 	if len(parts) > 1 {
@@ -1,7 +1,9 @@
 package telegram
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 )
 
@@ -20,7 +22,7 @@ func ExampleCommandParser() {
 	// Register a simple "hello" command
 	RegisterCommandFunc("hello", func(command *Command) error {
 		response := fmt.Sprintf("Hello, %s!", command.Message.From.FirstName)
-		return PushTextMessage(command.Message.Chat.ID, command.Message.MessageID, response)
+		return PushTextMessage(command.Context(), command.Message.Chat.ID, command.Message.MessageID, response)
 		//return bot.SendMessage(command.Message.Chat.ID, command.Message.MessageID, response)
 	})
 
@@ -30,32 +32,37 @@ func ExampleCommandParser() {
 	// Register a "echo" command with arguments
 	RegisterCommandFunc("echo", func(command *Command) error {
 		if len(command.Arguments) == 0 {
-			return PushTextMessage(command.Message.Chat.ID, command.Message.MessageID, "Usage: /echo <text>")
+			return PushTextMessage(command.Context(), command.Message.Chat.ID, command.Message.MessageID, "Usage: /echo <text>")
 			//return bot.SendMessage(command.Message.Chat.ID, command.Message.MessageID, "Usage: /echo <text>")
 		}
 
 		response := strings.Join(command.Arguments, " ")
-		return PushTextMessage(command.Message.Chat.ID, command.Message.MessageID, response)
+		return PushTextMessage(command.Context(), command.Message.Chat.ID, command.Message.MessageID, response)
 		//return bot.SendMessage(command.Message.Chat.ID, command.Message.MessageID, response)
 	})
 
 	// Add middleware to log commands
-	//Use(func(bot *BotClient, command *Command) error {
-	//	log.Printf("Processing command: %s, Arguments: %v", command.Name, command.Arguments)
-	//	// Return true to continue processing
-	//	return nil
-	//})
+	// Middleware runs for every routed update, so check Kind before using fields of a message
+	Use(func(command *Command) error {
+		log.Printf("Processing %s update, command: %s, Arguments: %v", command.Kind, command.Name, command.Arguments)
+		// Return nil to continue processing
+		return nil
+	})
 
 	// Add middleware to restrict access to certain users
-	//parser.Use(func(bot *BotClient, command *Command) bool {
-	//	// Only allow user with ID 123456 to execute commands
-	//	// Remove or modify this for your needs
-	//	if command.Name == "admin" && command.Message.From.ID != 123456 {
-	//		bot.SendMessage(command.Message.Chat.ID, "You don't have permission to use this command!")
-	//		return false // Stop processing
-	//	}
-	//	return true // Continue processing
-	//})
+	Use(func(command *Command) error {
+		// Inline queries, member updates and other non-message updates carry no Message
+		if command.Message == nil || command.Message.From == nil {
+			return nil
+		}
+		// Only allow user with ID 123456 to execute commands
+		// Remove or modify this for your needs
+		if command.Name == "admin" && command.Message.From.ID != 123456 {
+			_ = PushTextMessage(command.Context(), command.Message.Chat.ID, command.Message.MessageID, "You don't have permission to use this command!")
+			return errors.New("permission denied") // Stop processing
+		}
+		return nil // Continue processing
+	})
 
 	// Example of processing an update
 	// In a real application, this would be called from your webhook handler
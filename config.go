@@ -1,14 +1,18 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
 	"os"
+	"sync"
 	"time"
 )
 
 var (
 	botLog   = NewLog(os.Stdout)
 	botCache = make(map[string]*telegramBot)
+	// botMu 保护 botCache，WebhookHandler 可能在 RegisterBot 期间并发读取
+	botMu sync.RWMutex
 )
 
 type Config struct {
@@ -16,29 +20,102 @@ type Config struct {
 	Token    string
 	Webhook  string
 	MsgStore Store
+
+	// WebhookSecret 设置后 Telegram 在每次推送的 X-Telegram-Bot-Api-Secret-Token 头中携带该值
+	WebhookSecret string
+	// AllowedUpdates 需要接收的更新类型，如 UpdateMessage、UpdateCallbackQuery，为空时保持上次的设置；
+	// UpdateChatMember 必须显式列出才会推送
+	AllowedUpdates []string
+	// WebhookMaxConnections 同时推送的最大连接数 1-100，默认 40
+	WebhookMaxConnections int
+	// WebhookIPAddress 使用该 IP 推送而不是通过 DNS 解析 Webhook 域名
+	WebhookIPAddress string
+	// WebhookCertificate 使用自签名证书时上传的公钥证书
+	WebhookCertificate *InputFile
+	// DropPendingUpdates 为 true 时设置 Webhook 会丢弃尚未推送的更新
+	DropPendingUpdates bool
+
+	// APIEndpoint 为空时使用 DefaultAPIEndpoint，可指向自建 telegram-bot-api 服务或测试服务
+	APIEndpoint string
+	// FileEndpoint 为空时使用 APIEndpoint + "/file"
+	FileEndpoint string
+
+	// Logger 为空时使用包级默认日志，兼容 *slog.Logger；日志中的 token 总会被隐藏
+	Logger Logger
+	// RedactText 为 true 时日志中不输出消息正文、说明文字与响应内容
+	RedactText bool
+
+	// RateLimit 发送限流，默认全局 30 条/秒、私聊 1 条/秒、群组 20 条/分钟
+	RateLimit RateLimitConfig
+
+	// Transport 自定义 http.Client、RoundTripper、代理与 TLS
+	Transport TransportConfig
+	// RequestTimeout 请求未设置 deadline 时的兜底超时，默认 30 秒；下载文件时作为空闲超时，超过该时间没有收到数据即中止
+	RequestTimeout time.Duration
+	// PollTimeout getUpdates 长轮询超时，默认 50 秒
+	PollTimeout time.Duration
+
+	// Interceptors 包裹每次 Bot API 调用，可用于追踪、审计、故障注入与修改请求参数
+	Interceptors []Interceptor
+
+	// CacheTTL getMe、getChat、getChatMember 等查询结果的缓存时间，默认 1 分钟，负数禁用。
+	// 机器人自身的封禁、限制、提升等调用会立即清除缓存；其他管理员的修改通过 chat_member 更新清除，
+	// Telegram 只在 AllowedUpdates 显式包含 UpdateChatMember 时推送该更新，否则最长在 CacheTTL 后才能看到
+	CacheTTL time.Duration
+
+	// DisablePolling 为 true 时不启动 getUpdates 长轮询，由调用方自行接收更新（如挂载 WebhookHandler）。
+	// 默认在 Webhook 为空时自动开始轮询；若 Telegram 上仍设置着 webhook，轮询会被跳过并记录错误日志
+	DisablePolling bool
+	// DeleteWebhookBeforePolling 为 true 时，开始轮询前删除 Telegram 上仍然存在的 webhook，
+	// 未送达的更新会保留并通过轮询获取
+	DeleteWebhookBeforePolling bool
+
+	// SyncCommands 为 true 时注册机器人后将带描述的命令同步到 Telegram 命令菜单，
+	// 命令需在 RegisterBot 之前注册
+	SyncCommands bool
 }
 
 type telegramBot struct {
 	messageQueue
 }
 
-func RegisterBot(config *Config) error {
+// RegisterBot 注册机器人，ctx 结束时消息队列随之停止
+func RegisterBot(ctx context.Context, config *Config) error {
 	bot := &telegramBot{}
 
 	if config.Token == "" || config.MsgStore == nil {
 		return NewError(InvalidConfig)
 	}
 
-	bot.client = newBotClient(config.Token, config.Webhook)
+	client, err := newBotClient(ctx, config)
+	if err != nil {
+		return err
+	}
+	bot.client = client
+
+	if config.SyncCommands {
+		if err := client.syncCommands(ctx); err != nil {
+			return err
+		}
+	}
 
 	if config.MsgStore != nil {
 		bot.store = config.MsgStore
 		bot.size = 10
-		go bot.start()
+		go bot.start(ctx)
 		//bot.queue = newMessageQueue(config.MsgStore)
 	}
 
+	// 未设置 Webhook 时通过长轮询接收更新，offset 保存在实现了 OffsetStore 的 MsgStore 中
+	if config.Webhook == "" && !config.DisablePolling {
+		go func() {
+			_ = client.poll(ctx, config.MsgStore, config.DeleteWebhookBeforePolling)
+		}()
+	}
+
+	botMu.Lock()
 	botCache[config.Alias] = bot
+	botMu.Unlock()
 	return nil
 }
 
@@ -47,45 +124,227 @@ func newBot() *telegramBot {
 }
 
 func newBotUsing(alias string) *telegramBot {
+	botMu.RLock()
+	defer botMu.RUnlock()
 	return botCache[alias]
 }
 
-func (b *telegramBot) ProcessMessage(message *Message) error {
-	return b.client.processMessage(message)
+func (b *telegramBot) ProcessMessage(ctx context.Context, message *Message) error {
+	return b.client.processMessage(ctx, message)
 }
-func (b *telegramBot) PushMessage(message string) error {
+func (b *telegramBot) PushMessage(ctx context.Context, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	return b.store.RPush(message)
 }
 
-func ProcessMessage(message *Message) error {
-	return newBot().client.processMessage(message)
+func ProcessMessage(ctx context.Context, message *Message) error {
+	return newBot().client.processMessage(ctx, message)
+}
+
+func PushTextMessage(ctx context.Context, chatId int64, messageId int, message string, opts ...SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Message:   message,
+		Type:      MessageTypeText,
+		Options:   newSendOptions(opts),
+	})
+}
+
+func PushPhotoMessage(ctx context.Context, chatId int64, messageId int, imgUrl, caption string, opts ...SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      MessageTypePhoto,
+		ImgUrl:    imgUrl,
+		Caption:   caption,
+		Options:   replyOptions(messageId, opts),
+	})
+}
+
+// PushDocumentMessage 将文件消息放入队列，fileUrl 可以是 HTTP URL 或 file_id，messageId 大于 0 时回复该消息
+func PushDocumentMessage(ctx context.Context, chatId int64, messageId int, fileUrl, caption string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeDocument, chatId, messageId, fileUrl, caption, opts)
+}
+
+// PushAudioMessage 将音频消息放入队列
+func PushAudioMessage(ctx context.Context, chatId int64, messageId int, fileUrl, caption string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeAudio, chatId, messageId, fileUrl, caption, opts)
+}
+
+// PushVideoMessage 将视频消息放入队列
+func PushVideoMessage(ctx context.Context, chatId int64, messageId int, fileUrl, caption string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeVideo, chatId, messageId, fileUrl, caption, opts)
+}
+
+// PushVoiceMessage 将语音消息放入队列
+func PushVoiceMessage(ctx context.Context, chatId int64, messageId int, fileUrl, caption string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeVoice, chatId, messageId, fileUrl, caption, opts)
+}
+
+// PushAnimationMessage 将动图消息放入队列
+func PushAnimationMessage(ctx context.Context, chatId int64, messageId int, fileUrl, caption string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeAnimation, chatId, messageId, fileUrl, caption, opts)
+}
+
+// PushStickerMessage 将贴纸消息放入队列
+func PushStickerMessage(ctx context.Context, chatId int64, messageId int, fileUrl string, opts ...SendOption) error {
+	return pushMediaMessage(ctx, MessageTypeSticker, chatId, messageId, fileUrl, "", opts)
+}
+
+// PushLocationMessage 将位置消息放入队列
+func PushLocationMessage(ctx context.Context, chatId int64, messageId int, location Location, opts ...SendOption) error {
+	return pushPayloadMessage(ctx, MessageTypeLocation, chatId, messageId, location, opts)
 }
 
-func PushTextMessage(chatId int64, messageId int, message string) error {
-	msg := &telegramMessage{
-		ChatId:        chatId,
-		MessageId:     messageId,
-		Message:       message,
-		Type:          MessageTypeText,
-		RetryCount:    0,
-		RetryInterval: RetryInterval,
-		NextTime:      time.Now(),
+// PushVenueMessage 将地点消息放入队列
+func PushVenueMessage(ctx context.Context, chatId int64, messageId int, venue Venue, opts ...SendOption) error {
+	return pushPayloadMessage(ctx, MessageTypeVenue, chatId, messageId, venue, opts)
+}
+
+// PushContactMessage 将联系人消息放入队列
+func PushContactMessage(ctx context.Context, chatId int64, messageId int, contact Contact, opts ...SendOption) error {
+	return pushPayloadMessage(ctx, MessageTypeContact, chatId, messageId, contact, opts)
+}
+
+// PushPollMessage 将投票消息放入队列
+func PushPollMessage(ctx context.Context, chatId int64, messageId int, poll InputPoll, opts ...SendOption) error {
+	return pushPayloadMessage(ctx, MessageTypePoll, chatId, messageId, poll, opts)
+}
+
+// PushDiceMessage 将骰子消息放入队列，emoji 为空时使用 DiceDice
+func PushDiceMessage(ctx context.Context, chatId int64, messageId int, emoji string, opts ...SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Message:   emoji,
+		Type:      MessageTypeDice,
+		Options:   replyOptions(messageId, opts),
+	})
+}
+
+func pushMediaMessage(ctx context.Context, msgType string, chatId int64, messageId int, fileUrl, caption string, opts []SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      msgType,
+		FileUrl:   fileUrl,
+		Caption:   caption,
+		Options:   replyOptions(messageId, opts),
+	})
+}
+
+func pushPayloadMessage(ctx context.Context, msgType string, chatId int64, messageId int, payload interface{}, opts []SendOption) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
-	bytes, _ := json.Marshal(msg)
-	return newBot().PushMessage(string(bytes))
-}
-
-func PushPhotoMessage(chatId int64, messageId int, imgUrl, caption string) error {
-	msg := &telegramMessage{
-		ChatId:        chatId,
-		MessageId:     messageId,
-		Type:          MessageTypePhoto,
-		ImgUrl:        imgUrl,
-		Caption:       caption,
-		RetryCount:    0,
-		RetryInterval: RetryInterval,
-		NextTime:      time.Now(),
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      msgType,
+		Payload:   raw,
+		Options:   replyOptions(messageId, opts),
+	})
+}
+
+// replyOptions 在 messageId 大于 0 时让消息回复该消息，opts 中的 WithReplyTo 优先
+func replyOptions(messageId int, opts []SendOption) *sendOptions {
+	if messageId > 0 {
+		opts = append([]SendOption{WithReplyTo(messageId)}, opts...)
 	}
+	return newSendOptions(opts)
+}
+
+// PushEditTextMessage 将修改消息文本的操作放入队列
+func PushEditTextMessage(ctx context.Context, chatId int64, messageId int, message string, opts ...SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Message:   message,
+		Type:      MessageTypeEditText,
+		Options:   newSendOptions(opts),
+	})
+}
+
+// PushEditCaptionMessage 将修改图片说明的操作放入队列
+func PushEditCaptionMessage(ctx context.Context, chatId int64, messageId int, caption string, opts ...SendOption) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Caption:   caption,
+		Type:      MessageTypeEditCaption,
+		Options:   newSendOptions(opts),
+	})
+}
+
+// PushEditReplyMarkupMessage 将只修改内联键盘的操作放入队列，markup 为 nil 时移除键盘
+func PushEditReplyMarkupMessage(ctx context.Context, chatId int64, messageId int, markup *InlineKeyboardMarkup) error {
+	raw, err := json.Marshal(markup)
+	if err != nil {
+		return err
+	}
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      MessageTypeEditReplyMarkup,
+		Payload:   raw,
+	})
+}
+
+// PushEditMediaMessage 将替换消息媒体的操作放入队列，media.Media 只能是 URL 或 file_id
+func PushEditMediaMessage(ctx context.Context, chatId int64, messageId int, media InputMedia, opts ...SendOption) error {
+	if media.Media == nil || media.Media.needsUpload() {
+		return NewError(IllegalParameterError, "queued media must be a URL or file_id")
+	}
+	raw, err := json.Marshal(media)
+	if err != nil {
+		return err
+	}
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      MessageTypeEditMedia,
+		Payload:   raw,
+		Options:   newSendOptions(opts),
+	})
+}
+
+// PushDeleteMessage 将删除消息的操作放入队列
+func PushDeleteMessage(ctx context.Context, chatId int64, messageId int) error {
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:    chatId,
+		MessageId: messageId,
+		Type:      MessageTypeDelete,
+	})
+}
+
+// PushDeleteMessages 将批量删除消息的操作放入队列，找不到的消息会被跳过
+func PushDeleteMessages(ctx context.Context, chatId int64, messageIds []int) error {
+	raw, err := json.Marshal(messageIds)
+	if err != nil {
+		return err
+	}
+	return pushTelegramMessage(ctx, &telegramMessage{
+		ChatId:  chatId,
+		Type:    MessageTypeDeleteMany,
+		Payload: raw,
+	})
+}
+
+// pushTelegramMessage 初始化重试参数后将消息放入默认机器人的队列
+func pushTelegramMessage(ctx context.Context, msg *telegramMessage) error {
+	msg.RetryCount = 0
+	msg.RetryInterval = RetryInterval
+	msg.NextTime = time.Now()
 	bytes, _ := json.Marshal(msg)
-	return newBot().PushMessage(string(bytes))
+	return newBot().PushMessage(ctx, string(bytes))
+}
+
+// PushFormattedMessage 以纯文本加 entities 的形式将格式化消息放入队列，无需转义
+func PushFormattedMessage(ctx context.Context, chatId int64, messageId int, text FormattedText, opts ...SendOption) error {
+	plain, entities := text.Entities()
+	return PushTextMessage(ctx, chatId, messageId, plain, append(opts, WithEntities(entities))...)
 }
@@ -1,5 +1,12 @@
 package telegram
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
 const (
 	InvalidConfig         = 10400
 	RequestFailure        = 10401 //请求失败
@@ -10,6 +17,11 @@ const (
 	CommandNotFoundError  = 10413
 	IllegalParameterError = 10414
 	MessageTypeError      = 10415
+	NotEnoughRightsError  = 10416
+	FileTooLargeError     = 10417
+	ChatTypeError         = 10418
+	TargetIsAdminError    = 10419
+	NotModifiedError      = 10420
 )
 
 var errorMessage = map[int]string{
@@ -22,6 +34,48 @@ var errorMessage = map[int]string{
 	CommandNotFoundError:  "command not found",
 	IllegalParameterError: "illegal parameter",
 	MessageTypeError:      "Unsupported message type",
+	NotEnoughRightsError:  "not enough rights",
+	FileTooLargeError:     "file too large",
+	ChatTypeError:         "method is not available for this chat type",
+	TargetIsAdminError:    "target user is an administrator",
+	NotModifiedError:      "message is not modified",
+}
+
+// ErrNotEnoughRights matches, through errors.Is, API errors caused by the bot lacking administrator rights
+var ErrNotEnoughRights = NewError(NotEnoughRightsError)
+
+// ErrFileTooLarge is returned by downloads exceeding the allowed size
+var ErrFileTooLarge = NewError(FileTooLargeError)
+
+// ErrChatType matches API errors for methods the chat type does not support, such as supergroup-only methods in a basic group
+var ErrChatType = NewError(ChatTypeError)
+
+// ErrTargetIsAdmin matches API errors for actions that cannot be applied to the chat owner or an administrator
+var ErrTargetIsAdmin = NewError(TargetIsAdminError)
+
+// ErrNotModified matches API errors for edits that leave the message exactly as it was
+var ErrNotModified = NewError(NotModifiedError)
+
+// apiErrorDescriptions 是各哨兵错误对应的 Telegram 描述片段，供 errors.Is 匹配
+var apiErrorDescriptions = map[*Error][]string{
+	ErrNotEnoughRights: {
+		"not enough rights",
+		"CHAT_ADMIN_REQUIRED",
+		"need administrator rights",
+		"bot is not an administrator",
+		"RIGHT_FORBIDDEN",
+	},
+	ErrChatType: {
+		"method is available only for supergroups",
+		"method is available only for supergroup and channel chats",
+	},
+	ErrTargetIsAdmin: {
+		"can't remove chat owner",
+		"user is an administrator of the chat",
+	},
+	ErrNotModified: {
+		"message is not modified",
+	},
 }
 
 type Error struct {
@@ -49,3 +103,49 @@ func NewError(code int, msg ...string) *Error {
 		Msg:  message,
 	}
 }
+
+// APIError is returned when Telegram answers a request with ok=false
+type APIError struct {
+	Method      string              `json:"method"`
+	Code        int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+func (err *APIError) ErrorCode() int {
+	return err.Code
+}
+
+// Is lets errors.Is match ErrNotEnoughRights, ErrChatType, ErrTargetIsAdmin and ErrNotModified
+func (err *APIError) Is(target error) bool {
+	sentinel, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	for _, description := range apiErrorDescriptions[sentinel] {
+		if strings.Contains(err.Description, description) {
+			return true
+		}
+	}
+	return false
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (%d %s)", errorMessage[TelegramApiError], err.Method, err.Code, err.Description)
+}
+
+// RetryAfter returns how long Telegram asked to wait before repeating the request, or 0 when err is not a flood-control error
+func RetryAfter(err error) time.Duration {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.Parameters == nil || apiErr.Parameters.RetryAfter <= 0 {
+		return 0
+	}
+	return time.Duration(apiErr.Parameters.RetryAfter) * time.Second
+}
+
+// AsAPIError returns the *APIError wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
@@ -0,0 +1,262 @@
+package telegram
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	ParseModeMarkdownV2 = "MarkdownV2"
+	ParseModeHTML       = "HTML"
+)
+
+// MessageEntity types produced by the formatting helpers
+const (
+	EntityBold          = "bold"
+	EntityItalic        = "italic"
+	EntityUnderline     = "underline"
+	EntityStrikethrough = "strikethrough"
+	EntitySpoiler       = "spoiler"
+	EntityCode          = "code"
+	EntityPre           = "pre"
+	EntityTextLink      = "text_link"
+	EntityTextMention   = "text_mention"
+	EntityBlockquote    = "blockquote"
+)
+
+// TextPiece is one typed fragment of a FormattedText
+type TextPiece struct {
+	Kind     string // MessageEntity type, empty for plain text
+	Text     string
+	URL      string // text_link
+	Language string // pre
+	UserID   int64  // text_mention
+}
+
+// FormattedText is a message built from typed pieces that renders to MarkdownV2,
+// HTML or plain text with entities, escaping every piece as required
+type FormattedText []TextPiece
+
+// Format joins pieces into a FormattedText
+func Format(pieces ...TextPiece) FormattedText {
+	return pieces
+}
+
+func Plain(text string) TextPiece         { return TextPiece{Text: text} }
+func Bold(text string) TextPiece          { return TextPiece{Kind: EntityBold, Text: text} }
+func Italic(text string) TextPiece        { return TextPiece{Kind: EntityItalic, Text: text} }
+func Underline(text string) TextPiece     { return TextPiece{Kind: EntityUnderline, Text: text} }
+func Strikethrough(text string) TextPiece { return TextPiece{Kind: EntityStrikethrough, Text: text} }
+func Spoiler(text string) TextPiece       { return TextPiece{Kind: EntitySpoiler, Text: text} }
+func Code(text string) TextPiece          { return TextPiece{Kind: EntityCode, Text: text} }
+func Blockquote(text string) TextPiece    { return TextPiece{Kind: EntityBlockquote, Text: text} } // 总是独占一行或多行
+
+// Pre is a code block, language may be empty
+func Pre(text, language string) TextPiece {
+	return TextPiece{Kind: EntityPre, Text: text, Language: language}
+}
+
+// Link is text pointing to url
+func Link(text, url string) TextPiece {
+	return TextPiece{Kind: EntityTextLink, Text: text, URL: url}
+}
+
+// Mention links text to a user who may have no username
+func Mention(text string, userID int64) TextPiece {
+	return TextPiece{Kind: EntityTextMention, Text: text, UserID: userID}
+}
+
+// Append returns t with more pieces
+func (t FormattedText) Append(pieces ...TextPiece) FormattedText {
+	return append(t, pieces...)
+}
+
+// layout 在引用块前后补上换行，使引用块独占整行。MarkdownV2 的引用块必须从行首开始并在行尾结束，
+// 三种渲染都经过 layout，保证输出的文本一致
+func (t FormattedText) layout() FormattedText {
+	out := make(FormattedText, 0, len(t))
+	atLineStart := true // 已输出的文本为空或以换行结尾
+	quoteOpen := false  // 上一个非空片段是未以换行结尾的引用块
+	for _, p := range t {
+		if p.Text == "" {
+			continue
+		}
+		if p.Kind == EntityBlockquote && !atLineStart || quoteOpen && !strings.HasPrefix(p.Text, "\n") {
+			out = append(out, Plain("\n"))
+		}
+		out = append(out, p)
+		atLineStart = strings.HasSuffix(p.Text, "\n")
+		quoteOpen = p.Kind == EntityBlockquote && !atLineStart
+	}
+	return out
+}
+
+// String returns the text without any formatting
+func (t FormattedText) String() string {
+	var sb strings.Builder
+	for _, p := range t.layout() {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+// Entities returns the plain text and its entities, with offsets and lengths in UTF-16 code units
+func (t FormattedText) Entities() (string, []MessageEntity) {
+	var sb strings.Builder
+	var entities []MessageEntity
+	offset := 0
+	for _, p := range t.layout() {
+		length := utf16Len(p.Text)
+		if p.Kind != "" && length > 0 {
+			entity := MessageEntity{Type: p.Kind, Offset: offset, Length: length}
+			switch p.Kind {
+			case EntityTextLink:
+				entity.URL = p.URL
+			case EntityTextMention:
+				entity.User = &User{ID: p.UserID}
+			case EntityPre:
+				entity.Language = p.Language
+			}
+			entities = append(entities, entity)
+		}
+		sb.WriteString(p.Text)
+		offset += length
+	}
+	return sb.String(), entities
+}
+
+// MarkdownV2 renders t for parse_mode MarkdownV2
+func (t FormattedText) MarkdownV2() string {
+	var sb strings.Builder
+	for _, p := range t.layout() {
+		piece, rendered := p.markdownV2(), sb.String()
+		// 相邻片段的标记符会被合并识别（如 _ 与 __），用会被忽略的 \r 分隔
+		if rendered != "" && strings.ContainsAny(piece[:1], "_*~|`") && rendered[len(rendered)-1] == piece[0] {
+			sb.WriteByte('\r')
+		}
+		sb.WriteString(piece)
+	}
+	return sb.String()
+}
+
+func (p TextPiece) markdownV2() string {
+	text := EscapeMarkdownV2(p.Text)
+	switch p.Kind {
+	case EntityBold:
+		return "*" + text + "*"
+	case EntityItalic:
+		return "_" + text + "_"
+	case EntityUnderline:
+		return "__" + text + "__"
+	case EntityStrikethrough:
+		return "~" + text + "~"
+	case EntitySpoiler:
+		return "||" + text + "||"
+	case EntityCode:
+		return "`" + escapeMarkdownV2Code(p.Text) + "`"
+	case EntityPre:
+		return "```" + p.Language + "\n" + escapeMarkdownV2Code(p.Text) + "\n```"
+	case EntityTextLink:
+		return "[" + text + "](" + escapeMarkdownV2URL(p.URL) + ")"
+	case EntityTextMention:
+		return "[" + text + "](tg://user?id=" + strconv.FormatInt(p.UserID, 10) + ")"
+	case EntityBlockquote:
+		// 结尾的换行不属于引用，否则下一行也会被当作引用
+		body, newline := strings.CutSuffix(text, "\n")
+		quote := ">" + strings.ReplaceAll(body, "\n", "\n>")
+		if newline {
+			quote += "\n"
+		}
+		return quote
+	default:
+		return text
+	}
+}
+
+// HTML renders t for parse_mode HTML
+func (t FormattedText) HTML() string {
+	var sb strings.Builder
+	for _, p := range t.layout() {
+		sb.WriteString(p.html())
+	}
+	return sb.String()
+}
+
+func (p TextPiece) html() string {
+	text := html.EscapeString(p.Text)
+	switch p.Kind {
+	case EntityBold:
+		return "<b>" + text + "</b>"
+	case EntityItalic:
+		return "<i>" + text + "</i>"
+	case EntityUnderline:
+		return "<u>" + text + "</u>"
+	case EntityStrikethrough:
+		return "<s>" + text + "</s>"
+	case EntitySpoiler:
+		return "<tg-spoiler>" + text + "</tg-spoiler>"
+	case EntityCode:
+		return "<code>" + text + "</code>"
+	case EntityPre:
+		if p.Language == "" {
+			return "<pre>" + text + "</pre>"
+		}
+		return `<pre><code class="language-` + html.EscapeString(p.Language) + `">` + text + "</code></pre>"
+	case EntityTextLink:
+		return `<a href="` + html.EscapeString(p.URL) + `">` + text + "</a>"
+	case EntityTextMention:
+		return `<a href="tg://user?id=` + strconv.FormatInt(p.UserID, 10) + `">` + text + "</a>"
+	case EntityBlockquote:
+		return "<blockquote>" + text + "</blockquote>"
+	default:
+		return text
+	}
+}
+
+// markdownV2Special 是 MarkdownV2 中需要转义的字符
+const markdownV2Special = "_*[]()~`>#+-=|{}.!\\"
+
+// EscapeMarkdownV2 escapes every character that has a meaning in MarkdownV2
+func EscapeMarkdownV2(text string) string {
+	return escapeChars(text, markdownV2Special)
+}
+
+// EscapeHTML escapes <, >, & and quotes for parse_mode HTML
+func EscapeHTML(text string) string {
+	return html.EscapeString(text)
+}
+
+func escapeMarkdownV2Code(text string) string {
+	return escapeChars(text, "`\\")
+}
+
+func escapeMarkdownV2URL(url string) string {
+	return escapeChars(url, ")\\")
+}
+
+func escapeChars(text, chars string) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for _, r := range text {
+		if strings.ContainsRune(chars, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// utf16Len 返回 s 的 UTF-16 编码长度，Telegram 按此计算偏移与长度
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 && r <= unicode.MaxRune {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"html"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFormattedTextMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		text FormattedText
+		want string
+	}{
+		{"escape", Format(Plain("a_b*c[d](e)~f`g>h#i+j-k=l|m{n}o.p!q\\")), `a\_b\*c\[d\]\(e\)\~f\` + "`" + `g\>h\#i\+j\-k\=l\|m\{n\}o\.p\!q\\`},
+		{"bold", Format(Plain("x "), Bold("1.5")), `x *1\.5*`},
+		{"adjacent bold", Format(Bold("x"), Bold("y")), "*x*\r*y*"},
+		{"italic then underline", Format(Italic("a"), Underline("b")), "_a_\r__b__"},
+		{"different markers", Format(Bold("a"), Italic("b")), "*a*_b_"},
+		{"spoiler strike", Format(Spoiler("s"), Strikethrough("t")), "||s||~t~"},
+		{"code", Format(Code("a`b\\c_d")), "`a\\`b\\\\c_d`"},
+		{"pre", Format(Pre("x := `1`", "go")), "```go\nx := \\`1\\`\n```"},
+		{"link", Format(Link("a.b", "https://x.y/(z)")), `[a\.b](https://x.y/(z\))`},
+		{"mention", Format(Mention("u_1", 42)), `[u\_1](tg://user?id=42)`},
+		{"empty pieces skipped", Format(Bold(""), Plain("a"), Italic("")), "a"},
+		{"quote", Format(Blockquote("a\nb")), ">a\n>b"},
+		{"quote after text", Format(Plain("head"), Blockquote("q")), "head\n>q"},
+		{"quote after newline", Format(Plain("head\n"), Blockquote("q")), "head\n>q"},
+		{"text after quote", Format(Blockquote("q"), Plain(" tail")), ">q\n tail"},
+		{"newline after quote", Format(Blockquote("q"), Plain("\ntail")), ">q\ntail"},
+		{"quote ending in newline", Format(Blockquote("q\n"), Plain("tail")), ">q\ntail"},
+		{"adjacent quotes", Format(Blockquote("a"), Blockquote("b")), ">a\n>b"},
+		{"escaped quote", Format(Blockquote("1.")), `>1\.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.MarkdownV2(); got != tt.want {
+				t.Errorf("MarkdownV2() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormattedTextHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text FormattedText
+		want string
+	}{
+		{"escape", Format(Plain(`<a & "b">`)), "&lt;a &amp; &#34;b&#34;&gt;"},
+		{"styles", Format(Bold("b"), Italic("i"), Underline("u"), Strikethrough("s"), Spoiler("p")),
+			"<b>b</b><i>i</i><u>u</u><s>s</s><tg-spoiler>p</tg-spoiler>"},
+		{"code", Format(Code("a<b")), "<code>a&lt;b</code>"},
+		{"pre", Format(Pre("x", "")), "<pre>x</pre>"},
+		{"pre language", Format(Pre("x", "go")), `<pre><code class="language-go">x</code></pre>`},
+		{"link", Format(Link("a", `https://x.y/?a=1&b="2"`)), `<a href="https://x.y/?a=1&amp;b=&#34;2&#34;">a</a>`},
+		{"mention", Format(Mention("u", 42)), `<a href="tg://user?id=42">u</a>`},
+		{"quote after text", Format(Plain("head"), Blockquote("q")), "head\n<blockquote>q</blockquote>"},
+		{"text after quote", Format(Blockquote("q"), Plain(" tail")), "<blockquote>q</blockquote>\n tail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.text.HTML(); got != tt.want {
+				t.Errorf("HTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormattedTextEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     FormattedText
+		wantText string
+		want     []MessageEntity
+	}{
+		{"plain", Format(Plain("a")), "a", nil},
+		{"offsets", Format(Plain("ab "), Bold("cd"), Italic("e")), "ab cde", []MessageEntity{
+			{Type: EntityBold, Offset: 3, Length: 2},
+			{Type: EntityItalic, Offset: 5, Length: 1},
+		}},
+		{"surrogate pairs", Format(Plain("😀 "), Bold("ü😀")), "😀 ü😀", []MessageEntity{
+			{Type: EntityBold, Offset: 3, Length: 3},
+		}},
+		{"cjk", Format(Plain("中文"), Code("代码")), "中文代码", []MessageEntity{
+			{Type: EntityCode, Offset: 2, Length: 2},
+		}},
+		{"extras", Format(Link("l", "https://x.y"), Mention("m", 7), Pre("p", "go")), "lmp", []MessageEntity{
+			{Type: EntityTextLink, Offset: 0, Length: 1, URL: "https://x.y"},
+			{Type: EntityTextMention, Offset: 1, Length: 1, User: &User{ID: 7}},
+			{Type: EntityPre, Offset: 2, Length: 1, Language: "go"},
+		}},
+		{"empty piece", Format(Bold(""), Plain("a")), "a", nil},
+		{"quote boundaries", Format(Plain("h"), Blockquote("q"), Plain("t")), "h\nq\nt", []MessageEntity{
+			{Type: EntityBlockquote, Offset: 2, Length: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities := tt.text.Entities()
+			if text != tt.wantText {
+				t.Errorf("Entities() text = %q, want %q", text, tt.wantText)
+			}
+			if !reflect.DeepEqual(entities, tt.want) {
+				t.Errorf("Entities() = %+v, want %+v", entities, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormattedTextRenderersAgree 检查三种渲染去掉标记后得到相同的文本与换行
+func TestFormattedTextRenderersAgree(t *testing.T) {
+	htmlTag := regexp.MustCompile(`<[^>]*>`)
+	texts := []FormattedText{
+		Format(Blockquote("q"), Plain(" tail")),
+		Format(Plain("head"), Blockquote("a\nb"), Plain("tail")),
+		Format(Plain("head\n"), Blockquote("q\n"), Bold("b")),
+		Format(Blockquote("a"), Blockquote("b"), Italic("i")),
+		Format(Bold("x"), Bold("y"), Plain(" & <z>")),
+		Format(Plain("line\n"), Code("c"), Plain("\n"), Link("l", "https://x.y")),
+	}
+	for _, text := range texts {
+		plain, _ := text.Entities()
+		if s := text.String(); s != plain {
+			t.Errorf("String() = %q, Entities() text = %q", s, plain)
+		}
+		if stripped := html.UnescapeString(htmlTag.ReplaceAllString(text.HTML(), "")); stripped != plain {
+			t.Errorf("HTML() text = %q, Entities() text = %q", stripped, plain)
+		}
+		md := text.MarkdownV2()
+		if got, want := strings.Count(md, "\n"), strings.Count(plain, "\n"); got != want {
+			t.Errorf("MarkdownV2() %q has %d line breaks, Entities() text %q has %d", md, got, plain, want)
+		}
+	}
+}
@@ -0,0 +1,498 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// MaxInlineResults is the most results answerInlineQuery accepts per call
+const MaxInlineResults = 50
+
+// InlineQueryResult is one result of an inline query, one of the InlineQueryResult* types
+type InlineQueryResult interface {
+	resultType() string
+}
+
+// InputMessageContent is the message sent when an inline result is chosen, one of the
+// InputTextMessageContent, InputLocationMessageContent, InputVenueMessageContent,
+// InputContactMessageContent or InputInvoiceMessageContent types
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+// InputTextMessageContent sends a text message
+type InputTextMessageContent struct {
+	MessageText           string          `json:"message_text"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+}
+
+// InputLocationMessageContent sends a location
+type InputLocationMessageContent struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+// InputVenueMessageContent sends a venue
+type InputVenueMessageContent struct {
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	Title           string  `json:"title"`
+	Address         string  `json:"address"`
+	FoursquareID    string  `json:"foursquare_id,omitempty"`
+	FoursquareType  string  `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string  `json:"google_place_id,omitempty"`
+	GooglePlaceType string  `json:"google_place_type,omitempty"`
+}
+
+// InputContactMessageContent sends a contact
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	Vcard       string `json:"vcard,omitempty"`
+}
+
+// LabeledPrice represents a portion of the price for goods or services
+type LabeledPrice struct {
+	Label  string `json:"label"`
+	Amount int    `json:"amount"` // 以货币最小单位计
+}
+
+// InputInvoiceMessageContent sends an invoice
+type InputInvoiceMessageContent struct {
+	Title                     string         `json:"title"`
+	Description               string         `json:"description"`
+	Payload                   string         `json:"payload"`
+	ProviderToken             string         `json:"provider_token,omitempty"`
+	Currency                  string         `json:"currency"`
+	Prices                    []LabeledPrice `json:"prices"`
+	MaxTipAmount              int            `json:"max_tip_amount,omitempty"`
+	SuggestedTipAmounts       []int          `json:"suggested_tip_amounts,omitempty"`
+	ProviderData              string         `json:"provider_data,omitempty"`
+	PhotoURL                  string         `json:"photo_url,omitempty"`
+	NeedName                  bool           `json:"need_name,omitempty"`
+	NeedPhoneNumber           bool           `json:"need_phone_number,omitempty"`
+	NeedEmail                 bool           `json:"need_email,omitempty"`
+	NeedShippingAddress       bool           `json:"need_shipping_address,omitempty"`
+	SendPhoneNumberToProvider bool           `json:"send_phone_number_to_provider,omitempty"`
+	SendEmailToProvider       bool           `json:"send_email_to_provider,omitempty"`
+	IsFlexible                bool           `json:"is_flexible,omitempty"`
+}
+
+func (*InputTextMessageContent) inputMessageContent()     {}
+func (*InputLocationMessageContent) inputMessageContent() {}
+func (*InputVenueMessageContent) inputMessageContent()    {}
+func (*InputContactMessageContent) inputMessageContent()  {}
+func (*InputInvoiceMessageContent) inputMessageContent()  {}
+
+// InlineQueryResultArticle links to an article or web page
+type InlineQueryResultArticle struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	HideURL             bool                  `json:"hide_url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultPhoto links to a JPEG photo
+type InlineQueryResultPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoURL            string                `json:"photo_url"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	PhotoWidth          int                   `json:"photo_width,omitempty"`
+	PhotoHeight         int                   `json:"photo_height,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultGif links to an animated GIF file
+type InlineQueryResultGif struct {
+	ID                  string                `json:"id"`
+	GifURL              string                `json:"gif_url"`
+	GifWidth            int                   `json:"gif_width,omitempty"`
+	GifHeight           int                   `json:"gif_height,omitempty"`
+	GifDuration         int                   `json:"gif_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	ThumbnailMimeType   string                `json:"thumbnail_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultMpeg4Gif links to an H.264/MPEG-4 AVC video without sound
+type InlineQueryResultMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4URL            string                `json:"mpeg4_url"`
+	Mpeg4Width          int                   `json:"mpeg4_width,omitempty"`
+	Mpeg4Height         int                   `json:"mpeg4_height,omitempty"`
+	Mpeg4Duration       int                   `json:"mpeg4_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	ThumbnailMimeType   string                `json:"thumbnail_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVideo links to a page with an embedded video player or a video file
+type InlineQueryResultVideo struct {
+	ID                  string                `json:"id"`
+	VideoURL            string                `json:"video_url"`
+	MimeType            string                `json:"mime_type"` // "text/html" 或 "video/mp4"
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VideoWidth          int                   `json:"video_width,omitempty"`
+	VideoHeight         int                   `json:"video_height,omitempty"`
+	VideoDuration       int                   `json:"video_duration,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultAudio links to an MP3 audio file
+type InlineQueryResultAudio struct {
+	ID                  string                `json:"id"`
+	AudioURL            string                `json:"audio_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	Performer           string                `json:"performer,omitempty"`
+	AudioDuration       int                   `json:"audio_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVoice links to an OGG/OPUS voice recording
+type InlineQueryResultVoice struct {
+	ID                  string                `json:"id"`
+	VoiceURL            string                `json:"voice_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VoiceDuration       int                   `json:"voice_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultDocument links to a PDF or ZIP file
+type InlineQueryResultDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"` // "application/pdf" 或 "application/zip"
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultLocation is a location on a map
+type InlineQueryResultLocation struct {
+	ID                   string                `json:"id"`
+	Latitude             float64               `json:"latitude"`
+	Longitude            float64               `json:"longitude"`
+	Title                string                `json:"title"`
+	HorizontalAccuracy   float64               `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int                   `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL         string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth       int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight      int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultVenue is a venue
+type InlineQueryResultVenue struct {
+	ID                  string                `json:"id"`
+	Latitude            float64               `json:"latitude"`
+	Longitude           float64               `json:"longitude"`
+	Title               string                `json:"title"`
+	Address             string                `json:"address"`
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultContact is a contact with a phone number
+type InlineQueryResultContact struct {
+	ID                  string                `json:"id"`
+	PhoneNumber         string                `json:"phone_number"`
+	FirstName           string                `json:"first_name"`
+	LastName            string                `json:"last_name,omitempty"`
+	Vcard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+// InlineQueryResultGame is a game
+type InlineQueryResultGame struct {
+	ID            string                `json:"id"`
+	GameShortName string                `json:"game_short_name"`
+	ReplyMarkup   *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// InlineQueryResultCachedPhoto is a photo stored on the Telegram servers
+type InlineQueryResultCachedPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoFileID         string                `json:"photo_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedGif is an animated GIF stored on the Telegram servers
+type InlineQueryResultCachedGif struct {
+	ID                  string                `json:"id"`
+	GifFileID           string                `json:"gif_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedMpeg4Gif is a video without sound stored on the Telegram servers
+type InlineQueryResultCachedMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4FileID         string                `json:"mpeg4_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedSticker is a sticker stored on the Telegram servers
+type InlineQueryResultCachedSticker struct {
+	ID                  string                `json:"id"`
+	StickerFileID       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedDocument is a file stored on the Telegram servers
+type InlineQueryResultCachedDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileID      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVideo is a video stored on the Telegram servers
+type InlineQueryResultCachedVideo struct {
+	ID                  string                `json:"id"`
+	VideoFileID         string                `json:"video_file_id"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVoice is a voice message stored on the Telegram servers
+type InlineQueryResultCachedVoice struct {
+	ID                  string                `json:"id"`
+	VoiceFileID         string                `json:"voice_file_id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedAudio is an MP3 audio file stored on the Telegram servers
+type InlineQueryResultCachedAudio struct {
+	ID                  string                `json:"id"`
+	AudioFileID         string                `json:"audio_file_id"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (*InlineQueryResultArticle) resultType() string        { return "article" }
+func (*InlineQueryResultPhoto) resultType() string          { return "photo" }
+func (*InlineQueryResultGif) resultType() string            { return "gif" }
+func (*InlineQueryResultMpeg4Gif) resultType() string       { return "mpeg4_gif" }
+func (*InlineQueryResultVideo) resultType() string          { return "video" }
+func (*InlineQueryResultAudio) resultType() string          { return "audio" }
+func (*InlineQueryResultVoice) resultType() string          { return "voice" }
+func (*InlineQueryResultDocument) resultType() string       { return "document" }
+func (*InlineQueryResultLocation) resultType() string       { return "location" }
+func (*InlineQueryResultVenue) resultType() string          { return "venue" }
+func (*InlineQueryResultContact) resultType() string        { return "contact" }
+func (*InlineQueryResultGame) resultType() string           { return "game" }
+func (*InlineQueryResultCachedPhoto) resultType() string    { return "photo" }
+func (*InlineQueryResultCachedGif) resultType() string      { return "gif" }
+func (*InlineQueryResultCachedMpeg4Gif) resultType() string { return "mpeg4_gif" }
+func (*InlineQueryResultCachedSticker) resultType() string  { return "sticker" }
+func (*InlineQueryResultCachedDocument) resultType() string { return "document" }
+func (*InlineQueryResultCachedVideo) resultType() string    { return "video" }
+func (*InlineQueryResultCachedVoice) resultType() string    { return "voice" }
+func (*InlineQueryResultCachedAudio) resultType() string    { return "audio" }
+
+// InlineQueryResultsButton is shown above the inline results and opens a web app or a private chat with the bot
+type InlineQueryResultsButton struct {
+	Text           string      `json:"text"`
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"`
+}
+
+// InlineAnswer configures how an inline query answer is cached and paginated
+type InlineAnswer struct {
+	CacheTime  int    // 服务器缓存结果的秒数，0 使用 Telegram 默认的 300 秒
+	IsPersonal bool   // 结果只对发起查询的用户缓存
+	NextOffset string // 客户端加载更多结果时作为 InlineQuery.Offset 传回，为空表示没有更多结果
+	Button     *InlineQueryResultsButton
+}
+
+// AnswerInlineQuery sends up to MaxInlineResults results for an inline query
+func (b *botClient) answerInlineQuery(ctx context.Context, queryID string, results []InlineQueryResult, answer InlineAnswer) error {
+
+	encoded, err := encodeInlineResults(results)
+	if err != nil {
+		return err
+	}
+	params := map[string]interface{}{
+		"inline_query_id": queryID,
+		"results":         encoded,
+	}
+	if answer.CacheTime > 0 {
+		params["cache_time"] = answer.CacheTime
+	}
+	if answer.IsPersonal {
+		params["is_personal"] = true
+	}
+	if answer.NextOffset != "" {
+		params["next_offset"] = answer.NextOffset
+	}
+	if answer.Button != nil {
+		params["button"] = answer.Button
+	}
+	_, err = callAPI[bool](ctx, b, "answerInlineQuery", params)
+	return err
+}
+
+// encodeInlineResults 编码结果并在每个对象开头写入 type 字段
+func encodeInlineResults(results []InlineQueryResult) ([]json.RawMessage, error) {
+	encoded := make([]json.RawMessage, 0, len(results))
+	for _, result := range results {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		// 每种结果都有必填的 id，编码结果不会是空对象；nil 指针编码为 null
+		if len(raw) < 2 || raw[0] != '{' {
+			return nil, NewError(IllegalParameterError, "inline query result is nil")
+		}
+		encoded = append(encoded, append([]byte(`{"type":`+strconv.Quote(result.resultType())+`,`), raw[1:]...))
+	}
+	return encoded, nil
+}
+
+// PaginateInlineResults returns the page of results starting at offset, the InlineQuery.Offset of
+// the query being answered, and the next_offset for the following page, empty after the last page.
+// pageSize is capped at MaxInlineResults.
+func PaginateInlineResults(results []InlineQueryResult, offset string, pageSize int) ([]InlineQueryResult, string) {
+	if pageSize <= 0 || pageSize > MaxInlineResults {
+		pageSize = MaxInlineResults
+	}
+	start, err := strconv.Atoi(offset)
+	if err != nil || start < 0 {
+		start = 0
+	}
+	if start >= len(results) {
+		return nil, ""
+	}
+	end := min(start+pageSize, len(results))
+	if end == len(results) {
+		return results[start:end], ""
+	}
+	return results[start:end], strconv.Itoa(end)
+}
+
+// InlineQueryHandlerFunc handles an inline query
+type InlineQueryHandlerFunc func(query *InlineQueryContext) error
+
+// InlineQueryContext is passed to inline query handlers
+type InlineQueryContext struct {
+	*Command
+	Query *InlineQuery
+}
+
+// Answer sends results for the query
+func (c *InlineQueryContext) Answer(results []InlineQueryResult, answer InlineAnswer) error {
+	return c.client.answerInlineQuery(c.Context(), c.Query.ID, results, answer)
+}
+
+// AnswerPage sends the page of results requested by the query offset and sets next_offset for the following page
+func (c *InlineQueryContext) AnswerPage(results []InlineQueryResult, pageSize int, answer InlineAnswer) error {
+	page, next := PaginateInlineResults(results, c.Query.Offset, pageSize)
+	answer.NextOffset = next
+	return c.Answer(page, answer)
+}
+
+// OnInlineQuery 处理 @bot 内联查询，与命令共用 Use 注册的中间件
+func OnInlineQuery(handler InlineQueryHandlerFunc) {
+	OnUpdate(UpdateInlineQuery, func(command *Command) error {
+		return handler(&InlineQueryContext{Command: command, Query: command.Update.InlineQuery})
+	})
+}
@@ -0,0 +1,271 @@
+package telegram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// InputFile represents a file to be sent: an existing file_id, an HTTP URL,
+// a local path or a reader. Paths and readers are streamed as multipart/form-data.
+// A reader is read once; a request is only retried after a 429 when the reader is an io.Seeker.
+type InputFile struct {
+	FileID string
+	URL    string
+	Path   string
+	Name   string
+	Reader io.Reader
+
+	data []byte // FileFromBytes 的内容，创建后只读
+
+	mu       sync.Mutex // 保护 reader 的读取状态，同一文件可能被多个请求并发上传
+	consumed bool       // 不可 seek 的 reader 已被读取
+	seeked   bool       // start 已记录
+	start    int64      // 可 seek 的 reader 首次上传时的位置，重试时回到该位置
+}
+
+// FileFromID references a file already stored on the Telegram servers
+func FileFromID(fileID string) *InputFile {
+	return &InputFile{FileID: fileID}
+}
+
+// FileFromURL lets Telegram download the file from an HTTP URL
+func FileFromURL(url string) *InputFile {
+	return &InputFile{URL: url}
+}
+
+// FileFromPath uploads a local file
+func FileFromPath(path string) *InputFile {
+	return &InputFile{Path: path, Name: filepath.Base(path)}
+}
+
+// FileFromReader uploads the content of r under the given file name. r is streamed without buffering,
+// so a 429 is only retried when r implements io.Seeker; use FileFromBytes for small in-memory files.
+// Unlike paths and bytes, a reader cannot be sent by several requests at the same time.
+func FileFromReader(name string, r io.Reader) *InputFile {
+	return &InputFile{Name: name, Reader: r}
+}
+
+// FileFromBytes uploads data under the given file name
+func FileFromBytes(name string, data []byte) *InputFile {
+	return &InputFile{Name: name, data: data}
+}
+
+func (f *InputFile) needsUpload() bool {
+	return f != nil && (f.Reader != nil || f.Path != "" || f.data != nil)
+}
+
+// reference 返回非上传文件在请求中的字符串形式
+func (f *InputFile) reference() string {
+	if f.FileID != "" {
+		return f.FileID
+	}
+	return f.URL
+}
+
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.reference())
+}
+
+// UnmarshalJSON 还原队列中以字符串保存的 URL 或 file_id
+func (f *InputFile) UnmarshalJSON(data []byte) error {
+	var reference string
+	if err := json.Unmarshal(data, &reference); err != nil {
+		return err
+	}
+	if strings.HasPrefix(reference, "http://") || strings.HasPrefix(reference, "https://") {
+		f.URL = reference
+	} else {
+		f.FileID = reference
+	}
+	return nil
+}
+
+func (f *InputFile) fileName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if f.Path != "" {
+		return filepath.Base(f.Path)
+	}
+	return "file"
+}
+
+// replayable 判断文件能否在重试时再次上传：reader 必须可以 seek 回起始位置
+func (f *InputFile) replayable() bool {
+	if f == nil || f.Reader == nil || f.data != nil {
+		return true
+	}
+	_, ok := f.Reader.(io.Seeker)
+	return ok
+}
+
+// open 打开待上传的内容，路径与 reader 都在上传时流式读取。
+// 可 seek 的 reader 每次回到首次上传时的位置，其他 reader 只能读取一次
+func (f *InputFile) open() (io.ReadCloser, error) {
+	if f.data != nil {
+		return io.NopCloser(bytes.NewReader(f.data)), nil
+	}
+	if f.Reader == nil {
+		return os.Open(f.Path)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	seeker, ok := f.Reader.(io.Seeker)
+	if !ok {
+		if f.consumed {
+			return nil, errors.New("reader has already been uploaded")
+		}
+		f.consumed = true
+		return io.NopCloser(f.Reader), nil
+	}
+	if !f.seeked {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		f.start, f.seeked = start, true
+	} else if _, err := seeker.Seek(f.start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(f.Reader), nil
+}
+
+// uploadsReplayable 判断 params 中的文件能否在重试时再次上传
+func uploadsReplayable(params map[string]interface{}) bool {
+	for _, value := range params {
+		switch v := value.(type) {
+		case *InputFile:
+			if !v.replayable() {
+				return false
+			}
+		case []InputMedia:
+			for i := range v {
+				if !v[i].Media.replayable() {
+					return false
+				}
+			}
+		case InputMedia:
+			if !v.Media.replayable() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// upload 表示 multipart 请求中的一个文件字段
+type upload struct {
+	field string
+	file  *InputFile
+}
+
+// collectUploads 找出 params 中需要上传的文件，返回不含这些文件的表单字段。
+// 媒体组中的文件在副本中替换为 attach:// 名称，不修改调用方的 InputFile，同一文件可并发发送
+func collectUploads(params map[string]interface{}) ([]upload, map[string]interface{}) {
+	var uploads []upload
+	form := make(map[string]interface{}, len(params))
+	attachMedia := func(media InputMedia) InputMedia {
+		if !media.Media.needsUpload() {
+			return media
+		}
+		field := "file" + strconv.Itoa(len(uploads))
+		uploads = append(uploads, upload{field: field, file: media.Media})
+		media.Media = &InputFile{FileID: "attach://" + field}
+		return media
+	}
+
+	for key, value := range params {
+		switch v := value.(type) {
+		case *InputFile:
+			if v.needsUpload() {
+				uploads = append(uploads, upload{field: key, file: v})
+				continue
+			}
+		case []InputMedia:
+			group := make([]InputMedia, len(v))
+			for i := range v {
+				group[i] = attachMedia(v[i])
+			}
+			value = group
+		case InputMedia:
+			value = attachMedia(v)
+		}
+		form[key] = value
+	}
+	return uploads, form
+}
+
+// encodeMultipart 将表单字段与待上传文件编码为 multipart/form-data。
+// 文件在发送请求时经 io.Pipe 流式写入，不在内存中保留整个请求体
+func encodeMultipart(form map[string]interface{}, uploads []upload) (io.ReadCloser, string, error) {
+	fields := make(map[string]string, len(form))
+	for key, value := range form {
+		switch v := value.(type) {
+		case string:
+			fields[key] = v
+		case *InputFile:
+			fields[key] = v.reference()
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to encode %s: %v", key, err)
+			}
+			fields[key] = string(raw)
+		}
+	}
+
+	// 在发送前打开所有文件，文件不存在等错误直接返回
+	files := make([]io.ReadCloser, 0, len(uploads))
+	for _, u := range uploads {
+		file, err := u.file.open()
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			return nil, "", fmt.Errorf("failed to read %s: %v", u.file.fileName(), err)
+		}
+		files = append(files, file)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(writer, fields, uploads, files))
+	}()
+	return pr, writer.FormDataContentType(), nil
+}
+
+// writeMultipart 写入表单字段与文件内容，完成后关闭所有文件
+func writeMultipart(writer *multipart.Writer, fields map[string]string, uploads []upload, files []io.ReadCloser) error {
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+	for i, u := range uploads {
+		part, err := writer.CreateFormFile(u.field, u.file.fileName())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, files[i]); err != nil {
+			return fmt.Errorf("failed to read %s: %v", u.file.fileName(), err)
+		}
+	}
+	return writer.Close()
+}
@@ -0,0 +1,26 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Invoker performs a Bot API call and returns the result field of the response envelope.
+// Failed calls return an *APIError, a transport error or the context's error.
+type Invoker func(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error)
+
+// Interceptor wraps an outgoing Bot API call. It may inspect or modify method and params,
+// short-circuit the call, or inspect the result and error returned by next.
+type Interceptor func(ctx context.Context, method string, params map[string]interface{}, next Invoker) (json.RawMessage, error)
+
+// chainInterceptors 将拦截器组合为一个 Invoker，interceptors[0] 最先执行
+func chainInterceptors(interceptors []Interceptor, final Invoker) Invoker {
+	invoker := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, params map[string]interface{}) (json.RawMessage, error) {
+			return interceptor(ctx, method, params, next)
+		}
+	}
+	return invoker
+}
@@ -0,0 +1,133 @@
+package telegram
+
+// InlineKeyboardBuilder builds an InlineKeyboardMarkup row by row
+type InlineKeyboardBuilder struct {
+	rows [][]InlineKeyboardButton
+}
+
+// NewInlineKeyboard starts an empty inline keyboard
+func NewInlineKeyboard() *InlineKeyboardBuilder {
+	return &InlineKeyboardBuilder{}
+}
+
+// Row appends a row of buttons
+func (kb *InlineKeyboardBuilder) Row(buttons ...InlineKeyboardButton) *InlineKeyboardBuilder {
+	if len(buttons) > 0 {
+		kb.rows = append(kb.rows, buttons)
+	}
+	return kb
+}
+
+// Build returns the keyboard markup
+func (kb *InlineKeyboardBuilder) Build() *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: kb.rows}
+}
+
+// InlineURLButton opens url when pressed
+func InlineURLButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// InlineCallbackButton sends a callback query with data when pressed
+func InlineCallbackButton(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// InlineWebAppButton launches the Web App at url when pressed
+func InlineWebAppButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// InlineSwitchButton asks the user to pick a chat and inserts the bot's username and query there
+func InlineSwitchButton(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// InlineSwitchCurrentChatButton inserts the bot's username and query in the current chat's input field
+func InlineSwitchCurrentChatButton(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
+
+// ReplyKeyboardBuilder builds a ReplyKeyboardMarkup row by row
+type ReplyKeyboardBuilder struct {
+	markup ReplyKeyboardMarkup
+}
+
+// NewReplyKeyboard starts an empty reply keyboard
+func NewReplyKeyboard() *ReplyKeyboardBuilder {
+	return &ReplyKeyboardBuilder{}
+}
+
+// Row appends a row of buttons
+func (kb *ReplyKeyboardBuilder) Row(buttons ...KeyboardButton) *ReplyKeyboardBuilder {
+	if len(buttons) > 0 {
+		kb.markup.Keyboard = append(kb.markup.Keyboard, buttons)
+	}
+	return kb
+}
+
+// Resize asks clients to fit the keyboard to its buttons
+func (kb *ReplyKeyboardBuilder) Resize() *ReplyKeyboardBuilder {
+	kb.markup.ResizeKeyboard = true
+	return kb
+}
+
+// OneTime hides the keyboard as soon as it has been used
+func (kb *ReplyKeyboardBuilder) OneTime() *ReplyKeyboardBuilder {
+	kb.markup.OneTimeKeyboard = true
+	return kb
+}
+
+// Persistent keeps the keyboard shown when the regular keyboard is hidden
+func (kb *ReplyKeyboardBuilder) Persistent() *ReplyKeyboardBuilder {
+	kb.markup.IsPersistent = true
+	return kb
+}
+
+// Placeholder sets the text shown in the input field while the keyboard is active
+func (kb *ReplyKeyboardBuilder) Placeholder(text string) *ReplyKeyboardBuilder {
+	kb.markup.InputFieldPlaceholder = text
+	return kb
+}
+
+// Selective shows the keyboard only to mentioned users and the sender of the replied message
+func (kb *ReplyKeyboardBuilder) Selective() *ReplyKeyboardBuilder {
+	kb.markup.Selective = true
+	return kb
+}
+
+// Build returns the keyboard markup
+func (kb *ReplyKeyboardBuilder) Build() *ReplyKeyboardMarkup {
+	markup := kb.markup
+	return &markup
+}
+
+// KeyboardTextButton sends its text as a message when pressed
+func KeyboardTextButton(text string) KeyboardButton {
+	return KeyboardButton{Text: text}
+}
+
+// KeyboardContactButton sends the user's phone number when pressed
+func KeyboardContactButton(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestContact: true}
+}
+
+// KeyboardLocationButton sends the user's current location when pressed
+func KeyboardLocationButton(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestLocation: true}
+}
+
+// KeyboardWebAppButton launches the Web App at url when pressed
+func KeyboardWebAppButton(text, url string) KeyboardButton {
+	return KeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// RemoveKeyboard removes the current custom keyboard
+func RemoveKeyboard(selective bool) *ReplyKeyboardRemove {
+	return &ReplyKeyboardRemove{RemoveKeyboard: true, Selective: selective}
+}
+
+// NewForceReply shows a reply interface with an optional input placeholder
+func NewForceReply(placeholder string) *ForceReply {
+	return &ForceReply{ForceReply: true, InputFieldPlaceholder: placeholder}
+}
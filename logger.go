@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Logger is the leveled, structured logger used by a bot. args are alternating
+// key/value pairs, so *slog.Logger can be used directly.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// redactedValue replaces message text in logs when Config.RedactText is set
+const redactedValue = "[REDACTED]"
+
+func (l *Log) Debug(msg string, args ...any) { l.output(slog.LevelDebug, msg, args) }
+func (l *Log) Info(msg string, args ...any)  { l.output(slog.LevelInfo, msg, args) }
+func (l *Log) Warn(msg string, args ...any)  { l.output(slog.LevelWarn, msg, args) }
+func (l *Log) Error(msg string, args ...any) { l.output(slog.LevelError, msg, args) }
+
+// SetLevel 设置最低输出级别，默认 Info
+func (l *Log) SetLevel(level slog.Level) {
+	l.level = level
+}
+
+func (l *Log) output(level slog.Level, msg string, args []any) {
+	if level < l.level {
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteByte(' ')
+	sb.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			fmt.Fprintf(&sb, " !BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&sb, " %v=%v", args[i], args[i+1])
+	}
+	_ = l.Output(3, sb.String())
+}
+
+// redactLogger 在输出前移除日志中的 bot token 与 webhook 密钥
+type redactLogger struct {
+	next     Logger
+	replacer *strings.Replacer
+}
+
+func newRedactLogger(next Logger, token string, secrets ...string) Logger {
+	if next == nil {
+		next = botLog
+	}
+	var pairs []string
+	if token != "" {
+		pairs = append(pairs, token, "<token>")
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			pairs = append(pairs, secret, "<secret>")
+		}
+	}
+	if len(pairs) == 0 {
+		return next
+	}
+	return &redactLogger{next: next, replacer: strings.NewReplacer(pairs...)}
+}
+
+func (l *redactLogger) Debug(msg string, args ...any) {
+	l.next.Debug(l.redact(msg), l.redactArgs(args)...)
+}
+func (l *redactLogger) Info(msg string, args ...any) {
+	l.next.Info(l.redact(msg), l.redactArgs(args)...)
+}
+func (l *redactLogger) Warn(msg string, args ...any) {
+	l.next.Warn(l.redact(msg), l.redactArgs(args)...)
+}
+func (l *redactLogger) Error(msg string, args ...any) {
+	l.next.Error(l.redact(msg), l.redactArgs(args)...)
+}
+
+func (l *redactLogger) redact(s string) string {
+	return l.replacer.Replace(s)
+}
+
+func (l *redactLogger) redactArgs(args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			out[i] = l.redact(v)
+		case error:
+			out[i] = l.redact(v.Error())
+		case fmt.Stringer:
+			out[i] = l.redact(v.String())
+		default:
+			out[i] = arg
+		}
+	}
+	return out
+}
+
+// secretParams 始终从日志中隐藏的参数，与 RedactText 无关
+var secretParams = map[string]bool{
+	"secret_token": true,
+}
+
+// redactParams 返回用于日志的参数副本，始终隐藏密钥，redactText 时同时隐藏消息正文与说明文字
+func redactParams(params map[string]interface{}, redactText bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		switch key {
+		case "text", "caption", "question", "explanation":
+			if redactText {
+				value = redactedValue
+			}
+		default:
+			if secretParams[key] {
+				value = redactedValue
+			}
+		}
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	pollLimit      = 100 // getUpdates 每次最多获取的更新数
+	pollMinBackoff = time.Second
+	pollMaxBackoff = 30 * time.Second
+)
+
+// poll 长轮询获取更新并逐条交给 processUpdate，offset 在每条更新处理后保存到 store。
+// Telegram 上仍设置着 webhook 时，deleteWebhook 为 true 则先删除，否则不轮询并返回错误；
+// ctx 结束时返回 nil；有其他实例在轮询时 Telegram 返回 409，此时停止并返回该错误
+func (b *botClient) poll(ctx context.Context, store Store, deleteWebhook bool) error {
+	if err := b.checkWebhook(ctx, deleteWebhook); err != nil {
+		return err
+	}
+	offsets, _ := store.(OffsetStore)
+
+	var offset int64
+	if offsets != nil {
+		saved, err := offsets.LoadOffset()
+		if err != nil {
+			b.log.Warn("[TelegramBot.Poll] failed to load offset", "error", err)
+		}
+		offset = saved
+	} else {
+		// offset 只保存在内存中，重启后 Telegram 会重新推送未确认的更新
+		b.log.Warn("[TelegramBot.Poll] MsgStore does not implement OffsetStore, offset is kept in memory only")
+	}
+
+	b.log.Info("[TelegramBot.Poll] polling start", "offset", offset)
+	backoff := pollMinBackoff
+	for ctx.Err() == nil {
+		updates, err := b.getUpdates(ctx, offset, pollLimit)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if apiErr, ok := AsAPIError(err); ok && apiErr.Code == http.StatusConflict {
+				b.log.Error("[TelegramBot.Poll] polling stopped", "error", err)
+				return err
+			}
+			b.log.Warn("[TelegramBot.Poll] getUpdates failed", "error", err, "retry_in", backoff)
+			sleepContext(ctx, backoff)
+			backoff = min(backoff*2, pollMaxBackoff)
+			continue
+		}
+		backoff = pollMinBackoff
+
+		for i := range updates {
+			update := &updates[i]
+			b.handleUpdate(ctx, update)
+
+			offset = update.UpdateID + 1
+			if offsets != nil {
+				if err := offsets.SaveOffset(offset); err != nil {
+					b.log.Warn("[TelegramBot.Poll] failed to save offset", "offset", offset, "error", err)
+				}
+			}
+		}
+	}
+	b.log.Info("[TelegramBot.Poll] polling stop", "offset", offset)
+	return nil
+}
+
+// checkWebhook 确认 Telegram 上没有设置 webhook，否则 getUpdates 只会返回 409。
+// 查询失败时不阻止轮询，由轮询中的 409 处理兜底
+func (b *botClient) checkWebhook(ctx context.Context, deleteWebhook bool) error {
+	info, err := b.getWebhookInfo(ctx)
+	if err != nil {
+		b.log.Warn("[TelegramBot.Poll] failed to get webhook info", "error", err)
+		return nil
+	}
+	if info == nil || info.URL == "" {
+		return nil
+	}
+	if !deleteWebhook {
+		err := NewError(InvalidConfig, "a webhook is still set, polling skipped; delete it or set DeleteWebhookBeforePolling")
+		b.log.Error("[TelegramBot.Poll] polling skipped", "webhook", info.URL, "pending_updates", info.PendingUpdateCount, "error", err)
+		return err
+	}
+	b.log.Warn("[TelegramBot.Poll] deleting webhook before polling", "webhook", info.URL)
+	return b.deleteWebhook(ctx, false)
+}
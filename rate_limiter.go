@@ -0,0 +1,154 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGlobalPerSecond  = 30 // 全局每秒消息数
+	defaultPrivatePerSecond = 1  // 私聊每秒消息数
+	defaultGroupPerMinute   = 20 // 群组每分钟消息数
+
+	bucketSweepSize     = 1024 // 会话桶超过该数量时清理空闲桶
+	bucketSweepInterval = time.Minute
+)
+
+// RateLimitConfig configures the outbound limiter; zero values use Telegram's documented limits
+type RateLimitConfig struct {
+	Disabled         bool
+	GlobalPerSecond  int
+	PrivatePerSecond int
+	GroupPerMinute   int
+}
+
+// tokenBucket 令牌桶，允许预占令牌使并发调用按顺序排队
+type tokenBucket struct {
+	capacity float64
+	rate     float64 // 每秒补充的令牌数
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(capacity int, per time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		rate:     float64(capacity) / per.Seconds(),
+		tokens:   float64(capacity),
+		last:     time.Now(),
+	}
+}
+
+func (tb *tokenBucket) refill(now time.Time) {
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.capacity {
+		tb.tokens = tb.capacity
+	}
+	tb.last = now
+}
+
+// reserve 预占一个令牌，返回需要等待的时长
+func (tb *tokenBucket) reserve(now time.Time) time.Duration {
+	tb.refill(now)
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// rateLimiter 按全局与会话维度限制发送频率，chat_id 为负数视为群组
+type rateLimiter struct {
+	mu        sync.Mutex
+	config    RateLimitConfig
+	global    *tokenBucket
+	chats     map[int64]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	if config.Disabled {
+		return nil
+	}
+	if config.GlobalPerSecond <= 0 {
+		config.GlobalPerSecond = defaultGlobalPerSecond
+	}
+	if config.PrivatePerSecond <= 0 {
+		config.PrivatePerSecond = defaultPrivatePerSecond
+	}
+	if config.GroupPerMinute <= 0 {
+		config.GroupPerMinute = defaultGroupPerMinute
+	}
+	return &rateLimiter{
+		config:    config,
+		global:    newTokenBucket(config.GlobalPerSecond, time.Second),
+		chats:     make(map[int64]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// wait 阻塞直到会话与全局令牌都可用，ctx 结束时返回其错误
+func (rl *rateLimiter) wait(ctx context.Context, method string, params map[string]interface{}) error {
+	if rl == nil || !isSendMethod(method) {
+		return nil
+	}
+	if chatID, ok := params["chat_id"].(int64); ok {
+		if err := rl.waitBucket(ctx, rl.chatBucket(chatID)); err != nil {
+			return err
+		}
+	}
+	return rl.waitBucket(ctx, rl.global)
+}
+
+func (rl *rateLimiter) waitBucket(ctx context.Context, tb *tokenBucket) error {
+	rl.mu.Lock()
+	delay := tb.reserve(time.Now())
+	rl.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if !sleepContext(ctx, delay) {
+		// 归还预占的令牌
+		rl.mu.Lock()
+		tb.tokens++
+		rl.mu.Unlock()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (rl *rateLimiter) chatBucket(chatID int64) *tokenBucket {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if len(rl.chats) > bucketSweepSize && now.Sub(rl.lastSweep) > bucketSweepInterval {
+		for id, tb := range rl.chats {
+			if tb.refill(now); tb.tokens >= tb.capacity {
+				delete(rl.chats, id)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	tb, ok := rl.chats[chatID]
+	if !ok {
+		if chatID < 0 {
+			tb = newTokenBucket(rl.config.GroupPerMinute, time.Minute)
+		} else {
+			tb = newTokenBucket(rl.config.PrivatePerSecond, time.Second)
+		}
+		rl.chats[chatID] = tb
+	}
+	return tb
+}
+
+// isSendMethod 判断 method 是否会向会话发送新消息
+func isSendMethod(method string) bool {
+	return strings.HasPrefix(method, "send") ||
+		strings.HasPrefix(method, "forwardMessage") ||
+		strings.HasPrefix(method, "copyMessage")
+}
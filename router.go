@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+)
+
+// updateHandlers 按更新类型注册的处理程序，与命令共用 Use 注册的中间件
+var updateHandlers = make(map[string]CommandHandlerFunc)
+
+// OnUpdate 为某一类更新注册处理程序，kind 为 UpdateMessage、UpdateCallbackQuery 等
+func OnUpdate(kind string, handler CommandHandlerFunc) {
+	updateHandlers[kind] = handler
+}
+
+// OnMessage 处理没有对应命令的消息，如普通文本、图片与未注册的命令
+func OnMessage(handler CommandHandlerFunc) { OnUpdate(UpdateMessage, handler) }
+
+// OnCallbackQuery 处理没有被 OnCallback、OnCallbackPattern 匹配的回调，需自行调用 answerCallbackQuery
+func OnCallbackQuery(handler CommandHandlerFunc) { OnUpdate(UpdateCallbackQuery, handler) }
+
+func OnEditedMessage(handler CommandHandlerFunc)      { OnUpdate(UpdateEditedMessage, handler) }
+func OnChannelPost(handler CommandHandlerFunc)        { OnUpdate(UpdateChannelPost, handler) }
+func OnEditedChannelPost(handler CommandHandlerFunc)  { OnUpdate(UpdateEditedChannelPost, handler) }
+func OnShippingQuery(handler CommandHandlerFunc)      { OnUpdate(UpdateShippingQuery, handler) }
+func OnPreCheckoutQuery(handler CommandHandlerFunc)   { OnUpdate(UpdatePreCheckoutQuery, handler) }
+func OnPoll(handler CommandHandlerFunc)               { OnUpdate(UpdatePoll, handler) }
+func OnPollAnswer(handler CommandHandlerFunc)         { OnUpdate(UpdatePollAnswer, handler) }
+func OnMyChatMember(handler CommandHandlerFunc)       { OnUpdate(UpdateMyChatMember, handler) }
+func OnChatMember(handler CommandHandlerFunc)         { OnUpdate(UpdateChatMember, handler) }
+func OnChatJoinRequest(handler CommandHandlerFunc)    { OnUpdate(UpdateChatJoinRequest, handler) }
+func OnChosenInlineResult(handler CommandHandlerFunc) { OnUpdate(UpdateChosenInlineResult, handler) }
+
+// updateKind 返回更新的类型，未知类型返回空字符串
+func updateKind(update *Update) string {
+	switch {
+	case update.Message != nil:
+		return UpdateMessage
+	case update.EditedMessage != nil:
+		return UpdateEditedMessage
+	case update.ChannelPost != nil:
+		return UpdateChannelPost
+	case update.EditedChannelPost != nil:
+		return UpdateEditedChannelPost
+	case update.CallbackQuery != nil:
+		return UpdateCallbackQuery
+	case update.ShippingQuery != nil:
+		return UpdateShippingQuery
+	case update.PreCheckoutQuery != nil:
+		return UpdatePreCheckoutQuery
+	case update.Poll != nil:
+		return UpdatePoll
+	case update.PollAnswer != nil:
+		return UpdatePollAnswer
+	case update.MyChatMember != nil:
+		return UpdateMyChatMember
+	case update.ChatMember != nil:
+		return UpdateChatMember
+	case update.ChatJoinRequest != nil:
+		return UpdateChatJoinRequest
+	case update.InlineQuery != nil:
+		return UpdateInlineQuery
+	case update.ChosenInlineResult != nil:
+		return UpdateChosenInlineResult
+	}
+	return ""
+}
+
+// updateMessage 返回更新中携带的消息，回调查询返回按钮所在的消息
+func updateMessage(update *Update) *Message {
+	switch {
+	case update.Message != nil:
+		return update.Message
+	case update.EditedMessage != nil:
+		return update.EditedMessage
+	case update.ChannelPost != nil:
+		return update.ChannelPost
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.Message
+	}
+	return nil
+}
+
+// updateChatKey 返回更新所属的聊天，没有聊天的更新使用发送者，用于保证同一聊天的更新按顺序处理
+func updateChatKey(update *Update) int64 {
+	if message := updateMessage(update); message != nil {
+		return message.Chat.ID
+	}
+	switch {
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From.ID
+	case update.ShippingQuery != nil:
+		return update.ShippingQuery.From.ID
+	case update.PreCheckoutQuery != nil:
+		return update.PreCheckoutQuery.From.ID
+	case update.PollAnswer != nil:
+		return update.PollAnswer.User.ID
+	case update.MyChatMember != nil:
+		return update.MyChatMember.Chat.ID
+	case update.ChatMember != nil:
+		return update.ChatMember.Chat.ID
+	case update.ChatJoinRequest != nil:
+		return update.ChatJoinRequest.Chat.ID
+	case update.InlineQuery != nil:
+		return update.InlineQuery.From.ID
+	case update.ChosenInlineResult != nil:
+		return update.ChosenInlineResult.From.ID
+	}
+	return 0
+}
+
+// routeUpdate 将更新交给对应的处理程序：已注册的命令优先，其余消息交给 OnMessage，
+// 其他类型交给 On* 注册的处理程序；没有处理程序的更新被忽略
+func (b *botClient) routeUpdate(ctx context.Context, update *Update) error {
+	kind := updateKind(update)
+	if kind == UpdateCallbackQuery {
+		return b.routeCallback(ctx, update)
+	}
+	handler := updateHandlers[kind]
+
+	if kind == UpdateMessage {
+		message := update.Message
+		// 图片等媒体消息的命令写在说明文字中
+		commandText := message.Text
+		if commandText == "" {
+			commandText = message.Caption
+		}
+		if command := b.parse.ParseCommand(ctx, commandText, message); command != nil && !b.addressedToOtherBot(ctx, command) {
+			if _, exists := commands[command.Name]; exists || handler == nil {
+				command.Update, command.client = update, b
+				return command.Handler()
+			}
+		}
+		if handler == nil {
+			if commandText == "" {
+				return nil
+			}
+			return NewError(CommandNotFoundError)
+		}
+	}
+
+	if handler == nil {
+		return nil
+	}
+	command := &Command{
+		Kind:    kind,
+		Update:  update,
+		Message: updateMessage(update),
+		ctx:     ctx,
+		client:  b,
+	}
+	switch {
+	case command.Message != nil:
+		command.RawText = command.Message.Text
+		if command.RawText == "" {
+			command.RawText = command.Message.Caption
+		}
+	case update.InlineQuery != nil:
+		command.RawText = update.InlineQuery.Query
+	case update.ChosenInlineResult != nil:
+		command.RawText = update.ChosenInlineResult.Query
+	}
+	return command.run(handler)
+}
+
+// addressedToOtherBot 判断 /cmd@BotName 形式的命令是否发给了其他机器人，无法获取自身用户名时视为发给自己
+func (b *botClient) addressedToOtherBot(ctx context.Context, command *Command) bool {
+	if command.mention == "" {
+		return false
+	}
+	me, err := b.getMe(ctx)
+	if err != nil || me == nil || me.Username == "" {
+		return false
+	}
+	return !strings.EqualFold(me.Username, command.mention)
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"encoding/json"
+)
+
+// SendOption customizes an outgoing message
+type SendOption func(*sendOptions)
+
+// sendOptions 可序列化，随队列消息一起保存
+type sendOptions struct {
+	ReplyMarkup         json.RawMessage `json:"replyMarkup,omitempty"`
+	DisableNotification bool            `json:"disableNotification,omitempty"`
+	ProtectContent      bool            `json:"protectContent,omitempty"`
+	ParseMode           string          `json:"parseMode,omitempty"`
+	Entities            []MessageEntity `json:"entities,omitempty"`
+	ReplyTo             int             `json:"replyTo,omitempty"`
+}
+
+// WithReplyMarkup attaches an inline keyboard, custom reply keyboard, keyboard removal or force reply
+func WithReplyMarkup(markup ReplyMarkup) SendOption {
+	return func(o *sendOptions) {
+		if markup == nil {
+			o.ReplyMarkup = nil
+			return
+		}
+		o.ReplyMarkup, _ = json.Marshal(markup)
+	}
+}
+
+// WithDisableNotification sends the message silently
+func WithDisableNotification() SendOption {
+	return func(o *sendOptions) {
+		o.DisableNotification = true
+	}
+}
+
+// WithProtectContent protects the message from forwarding and saving
+func WithProtectContent() SendOption {
+	return func(o *sendOptions) {
+		o.ProtectContent = true
+	}
+}
+
+// WithParseMode sets parse_mode, ParseModeMarkdownV2 or ParseModeHTML
+func WithParseMode(mode string) SendOption {
+	return func(o *sendOptions) {
+		o.ParseMode = mode
+	}
+}
+
+// WithEntities formats the text or caption with explicit entities instead of a parse mode
+func WithEntities(entities []MessageEntity) SendOption {
+	return func(o *sendOptions) {
+		o.Entities = entities
+	}
+}
+
+// WithReplyTo sends the message as a reply to messageID in the same chat
+func WithReplyTo(messageID int) SendOption {
+	return func(o *sendOptions) {
+		o.ReplyTo = messageID
+	}
+}
+
+func newSendOptions(opts []SendOption) *sendOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	o := &sendOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// apply 将选项写入请求参数
+func (o *sendOptions) apply(params map[string]interface{}) {
+	if o == nil {
+		return
+	}
+	if len(o.ReplyMarkup) > 0 {
+		params["reply_markup"] = o.ReplyMarkup
+	}
+	if o.DisableNotification {
+		params["disable_notification"] = true
+	}
+	if o.ProtectContent {
+		params["protect_content"] = true
+	}
+	// sendMessage 拆分后的回复链已设置 reply_parameters
+	if _, ok := params["reply_parameters"]; !ok && o.ReplyTo > 0 {
+		params["reply_parameters"] = map[string]interface{}{
+			"message_id": o.ReplyTo,
+		}
+	}
+	if o.ParseMode != "" {
+		params["parse_mode"] = o.ParseMode
+	}
+	if len(o.Entities) > 0 {
+		// 图片等媒体消息的格式作用于说明文字
+		if _, ok := params["caption"]; ok {
+			params["caption_entities"] = o.Entities
+		} else {
+			params["entities"] = o.Entities
+		}
+	}
+}
+
+// asOptions 将已保存的选项还原为 SendOption，供队列重发时使用
+func (o *sendOptions) asOptions() []SendOption {
+	if o == nil {
+		return nil
+	}
+	saved := *o
+	return []SendOption{func(target *sendOptions) { *target = saved }}
+}
+
+func (o *sendOptions) parseMode() string {
+	if o == nil {
+		return ""
+	}
+	return o.ParseMode
+}
+
+func (o *sendOptions) entities() []MessageEntity {
+	if o == nil {
+		return nil
+	}
+	return o.Entities
+}
+
+// forChunk 返回拆分后某一段使用的选项：entities 替换为该段的 entities，
+// 回复键盘只保留在最后一段
+func (o *sendOptions) forChunk(chunk textChunk, last bool) *sendOptions {
+	chunkOptions := &sendOptions{}
+	if o != nil {
+		*chunkOptions = *o
+	}
+	chunkOptions.Entities = chunk.entities
+	if !last {
+		chunkOptions.ReplyMarkup = nil
+	}
+	return chunkOptions
+}
@@ -0,0 +1,344 @@
+package telegram
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxMessageLength is the longest text of a single message, in UTF-16 code units
+	MaxMessageLength = 4096
+	// MaxCaptionLength is the longest media caption, in UTF-16 code units
+	MaxCaptionLength = 1024
+)
+
+// textChunk 是拆分后的一段文本及其 entities（偏移已按该段重新计算）
+type textChunk struct {
+	text     string
+	entities []MessageEntity
+}
+
+// splitText 按段落、行、单词边界将文本拆成不超过 limit 个 UTF-16 单位的若干段，
+// 不在 entity 或 HTML/MarkdownV2 标记内部断开
+func splitText(text string, entities []MessageEntity, parseMode string, limit int) []textChunk {
+	var chunks []textChunk
+	rest := textChunk{text: text, entities: entities}
+	for {
+		head, tail := splitHead(rest.text, rest.entities, parseMode, limit)
+		if strings.TrimSpace(head.text) != "" || len(head.entities) > 0 {
+			chunks = append(chunks, head)
+		}
+		if tail.text == "" {
+			return chunks
+		}
+		rest = tail
+	}
+}
+
+// 断开位置的安全级别
+const (
+	cutSafe   = iota // 不在任何格式内部
+	cutReopen        // 在格式内部，需要闭合后在下一段重新打开
+	cutNever         // 在标签、转义序列、链接或组合字符内部，不能断开
+)
+
+// splitHead 取出不超过 limit 的开头一段，返回该段与剩余部分
+func splitHead(text string, entities []MessageEntity, parseMode string, limit int) (textChunk, textChunk) {
+	if utf16Len(text) <= limit {
+		return textChunk{text: text, entities: entities}, textChunk{}
+	}
+
+	runes := []rune(text)
+	// offsets[i] 为第 i 个字符之前的 UTF-16 偏移
+	offsets := make([]int, len(runes)+1)
+	for i, r := range runes {
+		offsets[i+1] = offsets[i] + utf16Len(string(r))
+	}
+
+	end := 0
+	for end < len(runes) && offsets[end+1] <= limit {
+		end++
+	}
+	if end == 0 {
+		end = 1
+	}
+
+	levels := splitLevels(runes, offsets, entities, parseMode)
+	cut := chooseCut(runes, levels, end)
+
+	// 在格式内部断开时闭合并重新打开标记，闭合标记同样计入长度
+	closing, reopening := "", ""
+	for levels[cut] == cutReopen && parseMode != "" {
+		closing, reopening = reopenMarkup(runes[:cut], parseMode)
+		if cut <= 1 || offsets[cut]+utf16Len(closing) <= limit {
+			break
+		}
+		if cut = chooseCut(runes, levels, cut-1); levels[cut] != cutReopen {
+			closing, reopening = "", ""
+		}
+	}
+
+	// 重新打开标记后剩余部分不能比原文更长，否则无法推进，此时直接断开
+	if len(runes)-cut+len([]rune(reopening)) >= len(runes) {
+		cut, closing, reopening = end, "", ""
+	}
+
+	head := textChunk{text: string(runes[:cut]) + closing, entities: clipEntities(entities, 0, offsets[cut])}
+	tail := textChunk{
+		text:     reopening + string(runes[cut:]),
+		entities: clipEntities(entities, offsets[cut], offsets[len(runes)]),
+	}
+	return head, tail
+}
+
+// chooseCut 在 (0, end] 中选择断开位置：优先格式之外，其次格式内部；
+// 同一级别内按段落 > 换行 > 空白 > 任意位置的顺序选择
+func chooseCut(runes []rune, levels []int, end int) int {
+	// 避免拆出过短的片段，边界只在后半段寻找
+	floor := end / 2
+	preferences := []func(i int) bool{
+		func(i int) bool { return i >= 2 && runes[i-1] == '\n' && runes[i-2] == '\n' },
+		func(i int) bool { return runes[i-1] == '\n' },
+		func(i int) bool { return unicode.IsSpace(runes[i-1]) },
+	}
+	for _, level := range []int{cutSafe, cutReopen} {
+		for _, match := range preferences {
+			for i := end; i > floor; i-- {
+				if levels[i] == level && match(i) {
+					return i
+				}
+			}
+		}
+		for i := end; i > 0; i-- {
+			if levels[i] == level {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// splitLevels 计算每个位置（第 i 个字符之前）的断开安全级别
+func splitLevels(runes []rune, offsets []int, entities []MessageEntity, parseMode string) []int {
+	levels := make([]int, len(runes)+1)
+
+	switch parseMode {
+	case ParseModeHTML:
+		markHTMLLevels(runes, levels)
+	case ParseModeMarkdownV2:
+		markMarkdownV2Levels(runes, levels)
+	default:
+		for _, e := range entities {
+			for i := range levels {
+				if offsets[i] > e.Offset && offsets[i] < e.Offset+e.Length {
+					levels[i] = cutReopen
+				}
+			}
+		}
+	}
+
+	// 不拆开 emoji 修饰符、连接符与组合字符
+	for i := 1; i < len(runes); i++ {
+		if runes[i] == '\u200d' || runes[i-1] == '\u200d' || unicode.Is(unicode.Mn, runes[i]) ||
+			unicode.Is(unicode.Sk, runes[i]) || (runes[i] >= 0xFE00 && runes[i] <= 0xFE0F) {
+			levels[i] = cutNever
+		}
+	}
+	return levels
+}
+
+// markHTMLLevels 标记 HTML 标签与字符实体内部（不可断开）以及标签范围内（需重新打开）的位置
+func markHTMLLevels(runes []rune, levels []int) {
+	depth, inTag, inEntity := 0, false, false
+	for i, r := range runes {
+		switch {
+		case inTag || inEntity:
+			levels[i] = cutNever
+		case depth > 0:
+			levels[i] = cutReopen
+		}
+		switch {
+		case inTag:
+			if r == '>' {
+				inTag = false
+			}
+		case r == '<':
+			inTag = true
+			if i+1 < len(runes) && runes[i+1] == '/' {
+				depth--
+			} else {
+				depth++
+			}
+		case r == '&':
+			inEntity = true
+		case inEntity && (r == ';' || unicode.IsSpace(r)):
+			inEntity = false
+		}
+	}
+}
+
+// markMarkdownV2Levels 标记转义序列、标记符与链接内部（不可断开）以及格式范围内（需重新打开）的位置
+func markMarkdownV2Levels(runes []rune, levels []int) {
+	state := &markdownV2State{}
+	for i := 0; i < len(runes); {
+		if state.inLink {
+			levels[i] = cutNever
+		} else if len(state.open) > 0 {
+			levels[i] = cutReopen
+		}
+		width := state.step(runes, i)
+		for j := i + 1; j < i+width && j < len(levels); j++ {
+			levels[j] = cutNever
+		}
+		i += width
+	}
+}
+
+// markdownV2State 跟踪 MarkdownV2 中尚未闭合的格式标记
+type markdownV2State struct {
+	open   []string // 按打开顺序排列的标记，代码块为 "```lang\n"
+	inLink bool
+}
+
+func (st *markdownV2State) top() string {
+	if len(st.open) == 0 {
+		return ""
+	}
+	return st.open[len(st.open)-1]
+}
+
+func (st *markdownV2State) toggle(marker string) {
+	for i := len(st.open) - 1; i >= 0; i-- {
+		if st.open[i] == marker {
+			st.open = append(st.open[:i], st.open[i+1:]...)
+			return
+		}
+	}
+	st.open = append(st.open, marker)
+}
+
+// step 处理 runes[i] 开始的一个记号，返回其宽度
+func (st *markdownV2State) step(runes []rune, i int) int {
+	hasPrefix := func(marker string) bool {
+		return strings.HasPrefix(string(runes[i:min(i+len(marker), len(runes))]), marker)
+	}
+	r := runes[i]
+	if r == '\\' && i+1 < len(runes) {
+		return 2
+	}
+
+	top := st.top()
+	switch {
+	case strings.HasPrefix(top, "```"):
+		if hasPrefix("```") {
+			st.open = st.open[:len(st.open)-1]
+			return 3
+		}
+		return 1
+	case top == "`":
+		if r == '`' {
+			st.open = st.open[:len(st.open)-1]
+		}
+		return 1
+	case st.inLink:
+		if r == ')' {
+			st.inLink = false
+		}
+		return 1
+	case hasPrefix("```"):
+		// 代码块的语言标识一直到行尾
+		width := 3
+		for i+width < len(runes) && runes[i+width] != '\n' {
+			width++
+		}
+		if i+width < len(runes) {
+			width++
+		}
+		st.open = append(st.open, string(runes[i:i+width]))
+		return width
+	case r == '`':
+		st.open = append(st.open, "`")
+		return 1
+	case hasPrefix("__"), hasPrefix("||"):
+		st.toggle(string(runes[i : i+2]))
+		return 2
+	case r == '_' || r == '*' || r == '~':
+		st.toggle(string(r))
+		return 1
+	case r == '[':
+		st.inLink = true
+		return 1
+	}
+	return 1
+}
+
+// reopenMarkup 返回在 prefix 末尾断开时需要追加的闭合标记，以及下一段开头需要重新打开的标记
+func reopenMarkup(prefix []rune, parseMode string) (string, string) {
+	var closing, reopening strings.Builder
+	switch parseMode {
+	case ParseModeHTML:
+		var stack []string
+		for i := 0; i < len(prefix); i++ {
+			if prefix[i] != '<' {
+				continue
+			}
+			j := i
+			for j < len(prefix) && prefix[j] != '>' {
+				j++
+			}
+			tag := string(prefix[i:min(j+1, len(prefix))])
+			if strings.HasPrefix(tag, "</") {
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			} else {
+				stack = append(stack, tag)
+			}
+			i = j
+		}
+		for i := len(stack) - 1; i >= 0; i-- {
+			name := strings.TrimPrefix(stack[i], "<")
+			if k := strings.IndexAny(name, " >"); k >= 0 {
+				name = name[:k]
+			}
+			closing.WriteString("</" + name + ">")
+		}
+		for _, tag := range stack {
+			reopening.WriteString(tag)
+		}
+	case ParseModeMarkdownV2:
+		state := &markdownV2State{}
+		for i := 0; i < len(prefix); {
+			i += state.step(prefix, i)
+		}
+		for i := len(state.open) - 1; i >= 0; i-- {
+			marker := state.open[i]
+			// 代码块的闭合标记必须独占一行，已在行首时不再补换行
+			if strings.HasPrefix(marker, "```") {
+				marker = "```"
+				if len(prefix) > 0 && prefix[len(prefix)-1] != '\n' {
+					marker = "\n```"
+				}
+			}
+			closing.WriteString(marker)
+		}
+		for _, marker := range state.open {
+			reopening.WriteString(marker)
+		}
+	}
+	return closing.String(), reopening.String()
+}
+
+// clipEntities 返回与 [from, to) 相交的 entities，截断到该范围并以 from 为新的起点
+func clipEntities(entities []MessageEntity, from, to int) []MessageEntity {
+	var clipped []MessageEntity
+	for _, e := range entities {
+		start, end := max(e.Offset, from), min(e.Offset+e.Length, to)
+		if start >= end {
+			continue
+		}
+		e.Offset, e.Length = start-from, end-start
+		clipped = append(clipped, e)
+	}
+	return clipped
+}
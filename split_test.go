@@ -0,0 +1,179 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitHead(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		entities  []MessageEntity
+		parseMode string
+		limit     int
+		wantHead  string
+		wantTail  string
+	}{
+		{"fits", "hello", nil, "", 5, "hello", ""},
+		{"word boundary", "aaaa bbbb", nil, "", 6, "aaaa ", "bbbb"},
+		{"paragraph before line", "aa\n\nbb cc\ndd", nil, "", 11, "aa\n\nbb cc\n", "dd"},
+		{"no spaces", "abcdefghij", nil, "", 4, "abcd", "efghij"},
+		{"surrogate pairs", "😀😀😀", nil, "", 3, "😀", "😀😀"},
+		{"surrogate pair at limit", "a😀b", nil, "", 2, "a", "😀b"},
+		{"emoji modifier", "👍🏽👍🏽", nil, "", 5, "👍🏽", "👍🏽"},
+		{"html entity", "a&amp;b", nil, ParseModeHTML, 4, "a", "&amp;b"},
+		{"html tag", "ab<b>cd</b>", nil, ParseModeHTML, 4, "ab", "<b>cd</b>"},
+		{"html reopen", "<b>aaaa bbbb cccc</b>", nil, ParseModeHTML, 16, "<b>aaaa </b>", "<b>bbbb cccc</b>"},
+		{"html nested", "<b>bold <i>italic words</i> tail</b>", nil, ParseModeHTML, 24,
+			"<b>bold <i>itali</i></b>", "<b><i>c words</i> tail</b>"},
+		{"html attributes", `<a href="https://x.y">link text here</a>`, nil, ParseModeHTML, 30,
+			`<a href="https://x.y">link</a>`, `<a href="https://x.y"> text here</a>`},
+		{"markdown escape", `a\.b\.c\.d`, nil, ParseModeMarkdownV2, 4, `a\.b`, `\.c\.d`},
+		{"markdown nested", "*bold _italic words_ tail*", nil, ParseModeMarkdownV2, 16,
+			"*bold _italic _*", "*_words_ tail*"},
+		{"markdown code block", "```go\nline1\nline2\nline3\n```", nil, ParseModeMarkdownV2, 18,
+			"```go\nline1\n```", "```go\nline2\nline3\n```"},
+		{"markdown inline code", "`aaaa bbbb`", nil, ParseModeMarkdownV2, 8, "`aaaa `", "`bbbb`"},
+		{"entity", "hello world", []MessageEntity{{Type: EntityBold, Offset: 0, Length: 11}}, "", 6,
+			"hello ", "world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tail := splitHead(tt.text, tt.entities, tt.parseMode, tt.limit)
+			if head.text != tt.wantHead || tail.text != tt.wantTail {
+				t.Errorf("splitHead() = %q, %q, want %q, %q", head.text, tail.text, tt.wantHead, tt.wantTail)
+			}
+			if tt.parseMode == "" && head.text+tail.text != tt.text {
+				t.Errorf("splitHead() lost text: %q + %q != %q", head.text, tail.text, tt.text)
+			}
+		})
+	}
+}
+
+func TestSplitHeadEntities(t *testing.T) {
+	// 整段为斜体，其中 bold 的 UTF-16 偏移为 3，断开后两段的偏移都重新计算
+	entities := []MessageEntity{
+		{Type: EntityItalic, Offset: 0, Length: 17},
+		{Type: EntityBold, Offset: 3, Length: 9},
+	}
+	head, tail := splitHead("😀 bold text here", entities, "", 8)
+	if head.text != "😀 bold " || tail.text != "text here" {
+		t.Fatalf("splitHead() = %q, %q", head.text, tail.text)
+	}
+	wantHead := []MessageEntity{{Type: EntityItalic, Offset: 0, Length: 8}, {Type: EntityBold, Offset: 3, Length: 5}}
+	if !reflect.DeepEqual(head.entities, wantHead) {
+		t.Errorf("head entities = %+v, want %+v", head.entities, wantHead)
+	}
+	wantTail := []MessageEntity{{Type: EntityItalic, Offset: 0, Length: 9}, {Type: EntityBold, Offset: 0, Length: 4}}
+	if !reflect.DeepEqual(tail.entities, wantTail) {
+		t.Errorf("tail entities = %+v, want %+v", tail.entities, wantTail)
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	paragraph := strings.Repeat("word ", 100) + "\n\n"
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		limit     int
+		want      int
+	}{
+		{"short", "hello", "", MaxMessageLength, 1},
+		{"paragraphs", strings.Repeat(paragraph, 25), "", MaxMessageLength, 4},
+		{"no spaces", strings.Repeat("x", 10000), "", MaxMessageLength, 3},
+		{"surrogate pairs", strings.Repeat("😀", 3000), "", MaxMessageLength, 2},
+		{"cjk", strings.Repeat("中文", 2100), "", MaxMessageLength, 2},
+		{"html", "<b>" + strings.Repeat("a&amp;b ", 1000) + "</b>", ParseModeHTML, MaxMessageLength, 2},
+		{"markdown", "*" + strings.Repeat(`a\.b `, 1000) + "*", ParseModeMarkdownV2, MaxMessageLength, 2},
+		{"code block", "```\n" + strings.Repeat("line\n", 1000) + "```", ParseModeMarkdownV2, MaxMessageLength, 2},
+		{"whitespace only tail", "aaaa    ", "", 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitText(tt.text, nil, tt.parseMode, tt.limit)
+			if len(chunks) != tt.want {
+				t.Fatalf("splitText() returned %d chunks, want %d", len(chunks), tt.want)
+			}
+			var joined strings.Builder
+			for i, chunk := range chunks {
+				if n := utf16Len(chunk.text); n > tt.limit {
+					t.Errorf("chunk %d has %d UTF-16 units, limit %d", i, n, tt.limit)
+				}
+				if strings.ContainsRune(chunk.text, '�') {
+					t.Errorf("chunk %d contains a broken character", i)
+				}
+				joined.WriteString(chunk.text)
+			}
+			if tt.parseMode == "" && strings.TrimSpace(joined.String()) != strings.TrimSpace(tt.text) {
+				t.Errorf("splitText() chunks do not join back to the original text")
+			}
+		})
+	}
+}
+
+func TestSplitTextReopensMarkup(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		prefix    string
+		suffix    string
+	}{
+		{"html", "<b><i>" + strings.Repeat("word ", 1000) + "</i></b>", ParseModeHTML, "<b><i>", "</i></b>"},
+		{"markdown", "*_" + strings.Repeat("word ", 1000) + "_*", ParseModeMarkdownV2, "*_", "_*"},
+		{"code block", "```go\n" + strings.Repeat("x := 1\n", 1000) + "```", ParseModeMarkdownV2, "```go\n", "\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitText(tt.text, nil, tt.parseMode, MaxMessageLength)
+			if len(chunks) < 2 {
+				t.Fatalf("splitText() returned %d chunks", len(chunks))
+			}
+			for i, chunk := range chunks {
+				if !strings.HasPrefix(chunk.text, tt.prefix) || !strings.HasSuffix(chunk.text, tt.suffix) {
+					t.Errorf("chunk %d = %q...%q, want %q...%q", i,
+						chunk.text[:min(len(chunk.text), 10)], chunk.text[max(0, len(chunk.text)-10):], tt.prefix, tt.suffix)
+				}
+			}
+		})
+	}
+}
+
+func TestClipEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		entities []MessageEntity
+		from, to int
+		want     []MessageEntity
+	}{
+		{"inside", []MessageEntity{{Type: EntityBold, Offset: 5, Length: 2}}, 4, 10,
+			[]MessageEntity{{Type: EntityBold, Offset: 1, Length: 2}}},
+		{"before", []MessageEntity{{Type: EntityBold, Offset: 0, Length: 4}}, 4, 10, nil},
+		{"after", []MessageEntity{{Type: EntityBold, Offset: 10, Length: 2}}, 4, 10, nil},
+		{"straddles start", []MessageEntity{{Type: EntityBold, Offset: 2, Length: 4}}, 4, 10,
+			[]MessageEntity{{Type: EntityBold, Offset: 0, Length: 2}}},
+		{"straddles end", []MessageEntity{{Type: EntityItalic, Offset: 8, Length: 4}}, 4, 10,
+			[]MessageEntity{{Type: EntityItalic, Offset: 4, Length: 2}}},
+		{"covers range", []MessageEntity{{Type: EntityTextLink, Offset: 0, Length: 20, URL: "https://x.y"}}, 4, 10,
+			[]MessageEntity{{Type: EntityTextLink, Offset: 0, Length: 6, URL: "https://x.y"}}},
+		{"order kept", []MessageEntity{
+			{Type: EntityBold, Offset: 4, Length: 1},
+			{Type: EntityItalic, Offset: 0, Length: 3},
+			{Type: EntityCode, Offset: 6, Length: 1},
+		}, 4, 10, []MessageEntity{
+			{Type: EntityBold, Offset: 0, Length: 1},
+			{Type: EntityCode, Offset: 2, Length: 1},
+		}},
+		{"empty", nil, 0, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipEntities(tt.entities, tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clipEntities() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package telegram
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultDialTimeout     = 10 * time.Second
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// TransportConfig describes how the bot reaches the Bot API server.
+// HTTPClient wins over Transport, which wins over the remaining fields.
+type TransportConfig struct {
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+
+	// Proxy 代理地址，支持 http://、https:// 与 socks5://
+	Proxy           string
+	TLSConfig       *tls.Config
+	DialTimeout     time.Duration
+	IdleConnTimeout time.Duration
+}
+
+// newHTTPClient 按配置创建 http.Client，超时由每次请求的 context 控制
+func newHTTPClient(config TransportConfig) (*http.Client, error) {
+	if config.HTTPClient != nil {
+		return config.HTTPClient, nil
+	}
+	if config.Transport != nil {
+		return &http.Client{Transport: config.Transport}, nil
+	}
+
+	dialTimeout := config.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+	idleConnTimeout := config.IdleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.IdleConnTimeout = idleConnTimeout
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig
+	}
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, NewError(InvalidConfig, fmt.Sprintf("invalid proxy url: %v", err))
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, NewError(InvalidConfig, fmt.Sprintf("unsupported proxy scheme: %s", proxyURL.Scheme))
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: transport}, nil
+}
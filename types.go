@@ -3,6 +3,8 @@ package telegram
 import (
 	"io"
 	"log"
+	"log/slog"
+	"path/filepath"
 )
 
 const (
@@ -12,6 +14,21 @@ const (
 	MessageTypeSticker  = "sticker"  //表情
 	MessageTypeVideo    = "video"    //视频
 	MessageTypePhoto    = "photo"    // 图片
+
+	MessageTypeVoice     = "voice"     //语音消息
+	MessageTypeAnimation = "animation" //动图
+	MessageTypeLocation  = "location"  //位置
+	MessageTypeVenue     = "venue"     //地点
+	MessageTypeContact   = "contact"   //联系人
+	MessageTypePoll      = "poll"      //投票
+	MessageTypeDice      = "dice"      //骰子
+
+	MessageTypeEditText        = "edit_text"         //修改消息文本
+	MessageTypeEditCaption     = "edit_caption"      //修改图片说明
+	MessageTypeEditReplyMarkup = "edit_reply_markup" //修改内联键盘
+	MessageTypeEditMedia       = "edit_media"        //替换消息中的媒体
+	MessageTypeDelete          = "delete"            //删除消息
+	MessageTypeDeleteMany      = "delete_many"       //批量删除消息
 )
 
 type Store interface {
@@ -21,8 +38,16 @@ type Store interface {
 	Close() error
 }
 
+// OffsetStore is optionally implemented by a Store to persist the getUpdates offset,
+// so a restarted bot neither replays nor skips updates. LoadOffset returns 0 when nothing was saved.
+type OffsetStore interface {
+	LoadOffset() (int64, error)
+	SaveOffset(offset int64) error
+}
+
 type Log struct {
 	*log.Logger
+	level slog.Level
 }
 
 func NewLog(out io.Writer) *Log {
@@ -39,6 +64,11 @@ type User struct {
 	LastName     string `json:"last_name,omitempty"`
 	Username     string `json:"username,omitempty"`
 	LanguageCode string `json:"language_code,omitempty"`
+
+	IsPremium               bool `json:"is_premium,omitempty"`
+	CanJoinGroups           bool `json:"can_join_groups,omitempty"`
+	CanReadAllGroupMessages bool `json:"can_read_all_group_messages,omitempty"`
+	SupportsInlineQueries   bool `json:"supports_inline_queries,omitempty"`
 }
 
 // Chat represents a Telegram chat
@@ -61,6 +91,21 @@ type Chat struct {
 	CanSetStickerSet      bool             `json:"can_set_sticker_set,omitempty"`
 	LinkedChatID          int64            `json:"linked_chat_id,omitempty"`
 	Location              *ChatLocation    `json:"location,omitempty"`
+
+	// 以下字段仅由 getChat 返回
+	IsForum                            bool     `json:"is_forum,omitempty"`
+	ActiveUsernames                    []string `json:"active_usernames,omitempty"`
+	Bio                                string   `json:"bio,omitempty"`
+	HasPrivateForwards                 bool     `json:"has_private_forwards,omitempty"`
+	JoinToSendMessages                 bool     `json:"join_to_send_messages,omitempty"`
+	JoinByRequest                      bool     `json:"join_by_request,omitempty"`
+	HasAggressiveAntiSpamEnabled       bool     `json:"has_aggressive_anti_spam_enabled,omitempty"`
+	HasHiddenMembers                   bool     `json:"has_hidden_members,omitempty"`
+	HasVisibleHistory                  bool     `json:"has_visible_history,omitempty"`
+	MaxReactionCount                   int      `json:"max_reaction_count,omitempty"`
+	UnrestrictBoostCount               int      `json:"unrestrict_boost_count,omitempty"`
+	CustomEmojiStickerSetName          string   `json:"custom_emoji_sticker_set_name,omitempty"`
+	HasRestrictedVoiceAndVideoMessages bool     `json:"has_restricted_voice_and_video_messages,omitempty"`
 }
 
 // Message represents a message
@@ -82,6 +127,7 @@ type Message struct {
 	Text                  string             `json:"text,omitempty"`
 	Entities              []MessageEntity    `json:"entities,omitempty"`
 	CaptionEntities       []MessageEntity    `json:"caption_entities,omitempty"`
+	Animation             *Animation         `json:"animation,omitempty"`
 	Audio                 *Audio             `json:"audio,omitempty"`
 	Document              *Document          `json:"document,omitempty"`
 	Photo                 []PhotoSize        `json:"photo,omitempty"`
@@ -113,11 +159,12 @@ type Message struct {
 
 // MessageEntity represents one special entity in a text message
 type MessageEntity struct {
-	Type   string `json:"type"`
-	Offset int    `json:"offset"`
-	Length int    `json:"length"`
-	URL    string `json:"url,omitempty"`
-	User   *User  `json:"user,omitempty"`
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`
+	User     *User  `json:"user,omitempty"`
+	Language string `json:"language,omitempty"`
 }
 
 // PhotoSize represents one size of a photo or a file/sticker thumbnail
@@ -141,6 +188,19 @@ type Audio struct {
 	Thumb        *PhotoSize `json:"thumb,omitempty"`
 }
 
+// Animation represents a GIF or H.264/MPEG-4 AVC video without sound
+type Animation struct {
+	FileID       string     `json:"file_id"`
+	FileUniqueID string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumb        *PhotoSize `json:"thumb,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int        `json:"file_size,omitempty"`
+}
+
 // Document represents a general file
 type Document struct {
 	FileID       string     `json:"file_id"`
@@ -228,6 +288,35 @@ type Dice struct {
 	Value int    `json:"value"`
 }
 
+// Dice emojis accepted by sendDice
+const (
+	DiceDice        = "🎲"
+	DiceDart        = "🎯"
+	DiceBasketball  = "🏀"
+	DiceFootball    = "⚽"
+	DiceBowling     = "🎳"
+	DiceSlotMachine = "🎰"
+)
+
+// Poll types
+const (
+	PollTypeRegular = "regular"
+	PollTypeQuiz    = "quiz"
+)
+
+// InputPoll describes a poll to be sent
+type InputPoll struct {
+	Question              string   `json:"question"`
+	Options               []string `json:"options"`
+	IsAnonymous           *bool    `json:"is_anonymous,omitempty"` // 为空时 Telegram 默认匿名
+	Type                  string   `json:"type,omitempty"`         // PollTypeRegular 或 PollTypeQuiz
+	AllowsMultipleAnswers bool     `json:"allows_multiple_answers,omitempty"`
+	CorrectOptionID       int      `json:"correct_option_id"` // 测验的正确选项，从 0 开始，0 也是有效值
+	Explanation           string   `json:"explanation,omitempty"`
+	OpenPeriod            int      `json:"open_period,omitempty"` // 投票持续秒数，5-600
+	IsClosed              bool     `json:"is_closed,omitempty"`
+}
+
 // ChatPhoto represents a chat photo
 type ChatPhoto struct {
 	SmallFileID       string `json:"small_file_id"`
@@ -246,6 +335,38 @@ type ChatPermissions struct {
 	CanChangeInfo         bool `json:"can_change_info,omitempty"`
 	CanInviteUsers        bool `json:"can_invite_users,omitempty"`
 	CanPinMessages        bool `json:"can_pin_messages,omitempty"`
+	CanSendAudios         bool `json:"can_send_audios,omitempty"`
+	CanSendDocuments      bool `json:"can_send_documents,omitempty"`
+	CanSendPhotos         bool `json:"can_send_photos,omitempty"`
+	CanSendVideos         bool `json:"can_send_videos,omitempty"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes,omitempty"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes,omitempty"`
+	CanManageTopics       bool `json:"can_manage_topics,omitempty"`
+}
+
+// independent reports whether the granular media permissions are used instead of can_send_media_messages
+func (p ChatPermissions) independent() bool {
+	return p.CanSendAudios || p.CanSendDocuments || p.CanSendPhotos || p.CanSendVideos ||
+		p.CanSendVideoNotes || p.CanSendVoiceNotes
+}
+
+// ChatAdministratorRights represents the rights of an administrator in a chat, every flag is sent explicitly
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous"`
+	CanManageChat       bool `json:"can_manage_chat"`
+	CanDeleteMessages   bool `json:"can_delete_messages"`
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	CanRestrictMembers  bool `json:"can_restrict_members"`
+	CanPromoteMembers   bool `json:"can_promote_members"`
+	CanChangeInfo       bool `json:"can_change_info"`
+	CanInviteUsers      bool `json:"can_invite_users"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty"`
+	CanEditMessages     bool `json:"can_edit_messages,omitempty"`
+	CanPinMessages      bool `json:"can_pin_messages,omitempty"`
+	CanPostStories      bool `json:"can_post_stories,omitempty"`
+	CanEditStories      bool `json:"can_edit_stories,omitempty"`
+	CanDeleteStories    bool `json:"can_delete_stories,omitempty"`
+	CanManageTopics     bool `json:"can_manage_topics,omitempty"`
 }
 
 // ChatLocation represents a location to which a chat is connected
@@ -269,6 +390,41 @@ type Update struct {
 	MyChatMember      *ChatMemberUpdated `json:"my_chat_member,omitempty"`
 	ChatMember        *ChatMemberUpdated `json:"chat_member,omitempty"`
 	ChatJoinRequest   *ChatJoinRequest   `json:"chat_join_request,omitempty"`
+
+	InlineQuery        *InlineQuery        `json:"inline_query,omitempty"`
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result,omitempty"`
+}
+
+// Update types used in allowed_updates
+const (
+	UpdateMessage           = "message"
+	UpdateEditedMessage     = "edited_message"
+	UpdateChannelPost       = "channel_post"
+	UpdateEditedChannelPost = "edited_channel_post"
+	UpdateCallbackQuery     = "callback_query"
+	UpdateShippingQuery     = "shipping_query"
+	UpdatePreCheckoutQuery  = "pre_checkout_query"
+	UpdatePoll              = "poll"
+	UpdatePollAnswer        = "poll_answer"
+	UpdateMyChatMember      = "my_chat_member"
+	UpdateChatMember        = "chat_member"
+	UpdateChatJoinRequest   = "chat_join_request"
+
+	UpdateInlineQuery        = "inline_query"
+	UpdateChosenInlineResult = "chosen_inline_result"
+)
+
+// WebhookInfo describes the current status of a webhook
+type WebhookInfo struct {
+	URL                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int      `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address,omitempty"`
+	LastErrorDate                int64    `json:"last_error_date,omitempty"`
+	LastErrorMessage             string   `json:"last_error_message,omitempty"`
+	LastSynchronizationErrorDate int64    `json:"last_synchronization_error_date,omitempty"`
+	MaxConnections               int      `json:"max_connections,omitempty"`
+	AllowedUpdates               []string `json:"allowed_updates,omitempty"`
 }
 
 // CallbackQuery represents an incoming callback query from a callback button in an inline keyboard
@@ -282,6 +438,25 @@ type CallbackQuery struct {
 	GameShortName   string   `json:"game_short_name,omitempty"`
 }
 
+// InlineQuery represents an incoming inline query, sent when a user types @bot in any chat
+type InlineQuery struct {
+	ID       string    `json:"id"`
+	From     User      `json:"from"`
+	Query    string    `json:"query"`
+	Offset   string    `json:"offset"`
+	ChatType string    `json:"chat_type,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+// ChosenInlineResult represents an inline result chosen by a user and sent to their chat partner
+type ChosenInlineResult struct {
+	ResultID        string    `json:"result_id"`
+	From            User      `json:"from"`
+	Location        *Location `json:"location,omitempty"`
+	InlineMessageID string    `json:"inline_message_id,omitempty"`
+	Query           string    `json:"query"`
+}
+
 // ShippingQuery contains information about an incoming shipping query
 type ShippingQuery struct {
 	ID              string          `json:"id"`
@@ -331,6 +506,69 @@ type ChatJoinRequest struct {
 type ChatMember struct {
 	User   User   `json:"user"`
 	Status string `json:"status"` // "creator", "administrator", "member", "restricted", "left", "kicked"
+
+	// creator 与 administrator
+	IsAnonymous         bool   `json:"is_anonymous,omitempty"`
+	CustomTitle         string `json:"custom_title,omitempty"`
+	CanBeEdited         bool   `json:"can_be_edited,omitempty"`
+	CanManageChat       bool   `json:"can_manage_chat,omitempty"`
+	CanDeleteMessages   bool   `json:"can_delete_messages,omitempty"`
+	CanManageVideoChats bool   `json:"can_manage_video_chats,omitempty"`
+	CanRestrictMembers  bool   `json:"can_restrict_members,omitempty"`
+	CanPromoteMembers   bool   `json:"can_promote_members,omitempty"`
+	CanPostMessages     bool   `json:"can_post_messages,omitempty"`
+	CanEditMessages     bool   `json:"can_edit_messages,omitempty"`
+	CanPostStories      bool   `json:"can_post_stories,omitempty"`
+	CanEditStories      bool   `json:"can_edit_stories,omitempty"`
+	CanDeleteStories    bool   `json:"can_delete_stories,omitempty"`
+
+	// administrator 与 restricted
+	CanChangeInfo   bool `json:"can_change_info,omitempty"`
+	CanInviteUsers  bool `json:"can_invite_users,omitempty"`
+	CanPinMessages  bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics bool `json:"can_manage_topics,omitempty"`
+
+	// restricted
+	IsMember              bool `json:"is_member,omitempty"`
+	CanSendMessages       bool `json:"can_send_messages,omitempty"`
+	CanSendAudios         bool `json:"can_send_audios,omitempty"`
+	CanSendDocuments      bool `json:"can_send_documents,omitempty"`
+	CanSendPhotos         bool `json:"can_send_photos,omitempty"`
+	CanSendVideos         bool `json:"can_send_videos,omitempty"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes,omitempty"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes,omitempty"`
+	CanSendPolls          bool `json:"can_send_polls,omitempty"`
+	CanSendOtherMessages  bool `json:"can_send_other_messages,omitempty"`
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews,omitempty"`
+
+	// restricted 与 kicked，0 表示永久
+	UntilDate int64 `json:"until_date,omitempty"`
+}
+
+// Chat member statuses
+const (
+	ChatMemberCreator       = "creator"
+	ChatMemberAdministrator = "administrator"
+	ChatMemberMember        = "member"
+	ChatMemberRestricted    = "restricted"
+	ChatMemberLeft          = "left"
+	ChatMemberKicked        = "kicked"
+)
+
+// IsAdmin reports whether the member is the owner or an administrator of the chat
+func (m *ChatMember) IsAdmin() bool {
+	return m.Status == ChatMemberCreator || m.Status == ChatMemberAdministrator
+}
+
+// InChat reports whether the member currently belongs to the chat
+func (m *ChatMember) InChat() bool {
+	switch m.Status {
+	case ChatMemberCreator, ChatMemberAdministrator, ChatMemberMember:
+		return true
+	case ChatMemberRestricted:
+		return m.IsMember
+	}
+	return false
 }
 
 // ChatInviteLink represents an invite link for a chat
@@ -392,12 +630,17 @@ type File struct {
 	FilePath     string `json:"file_path,omitempty"`
 }
 
+// IsLocal reports whether the file path is an absolute path returned by a Bot API server running in local mode
+func (f *File) IsLocal() bool {
+	return filepath.IsAbs(f.FilePath)
+}
+
 // InputMedia represents the content of a media message to be sent
 type InputMedia struct {
-	Type      string `json:"type"`
-	Media     string `json:"media"`
-	Caption   string `json:"caption,omitempty"`
-	ParseMode string `json:"parse_mode,omitempty"`
+	Type      string     `json:"type"`
+	Media     *InputFile `json:"media"`
+	Caption   string     `json:"caption,omitempty"`
+	ParseMode string     `json:"parse_mode,omitempty"`
 }
 
 // Sticker represents a sticker
@@ -422,3 +665,126 @@ type MaskPosition struct {
 	YShift float64 `json:"y_shift"`
 	Scale  float64 `json:"scale"`
 }
+
+// apiResponse is the envelope every Bot API method responds with
+type apiResponse[T any] struct {
+	Ok          bool                `json:"ok"`
+	Result      T                   `json:"result,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+// ResponseParameters describes why a request was unsuccessful
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
+}
+
+// MessageID represents a unique message identifier
+type MessageID struct {
+	MessageID int `json:"message_id"`
+}
+
+// BotCommand represents a command shown in the command menu of Telegram clients
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// Bot command scope types
+const (
+	ScopeTypeDefault               = "default"
+	ScopeTypeAllPrivateChats       = "all_private_chats"
+	ScopeTypeAllGroupChats         = "all_group_chats"
+	ScopeTypeAllChatAdministrators = "all_chat_administrators"
+	ScopeTypeChat                  = "chat"
+	ScopeTypeChatAdministrators    = "chat_administrators"
+	ScopeTypeChatMember            = "chat_member"
+)
+
+// BotCommandScope represents the chats and users a list of bot commands applies to
+type BotCommandScope struct {
+	Type   string `json:"type"`
+	ChatID int64  `json:"chat_id,omitempty"`
+	UserID int64  `json:"user_id,omitempty"`
+}
+
+func ScopeDefault() BotCommandScope         { return BotCommandScope{Type: ScopeTypeDefault} }
+func ScopeAllPrivateChats() BotCommandScope { return BotCommandScope{Type: ScopeTypeAllPrivateChats} }
+func ScopeAllGroupChats() BotCommandScope   { return BotCommandScope{Type: ScopeTypeAllGroupChats} }
+func ScopeAllChatAdministrators() BotCommandScope {
+	return BotCommandScope{Type: ScopeTypeAllChatAdministrators}
+}
+func ScopeChat(chatID int64) BotCommandScope {
+	return BotCommandScope{Type: ScopeTypeChat, ChatID: chatID}
+}
+func ScopeChatAdministrators(chatID int64) BotCommandScope {
+	return BotCommandScope{Type: ScopeTypeChatAdministrators, ChatID: chatID}
+}
+
+// ScopeChatMember limits commands to one member of a group chat
+func ScopeChatMember(chatID, userID int64) BotCommandScope {
+	return BotCommandScope{Type: ScopeTypeChatMember, ChatID: chatID, UserID: userID}
+}
+
+// ReplyMarkup is implemented by InlineKeyboardMarkup, ReplyKeyboardMarkup, ReplyKeyboardRemove and ForceReply
+type ReplyMarkup interface {
+	replyMarkup()
+}
+
+// InlineKeyboardMarkup represents an inline keyboard that appears right next to the message it belongs to
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton represents one button of an inline keyboard, exactly one optional field must be used
+type InlineKeyboardButton struct {
+	Text                         string      `json:"text"`
+	URL                          string      `json:"url,omitempty"`
+	CallbackData                 string      `json:"callback_data,omitempty"`
+	WebApp                       *WebAppInfo `json:"web_app,omitempty"`
+	SwitchInlineQuery            *string     `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat *string     `json:"switch_inline_query_current_chat,omitempty"`
+}
+
+// ReplyKeyboardMarkup represents a custom keyboard with reply options
+type ReplyKeyboardMarkup struct {
+	Keyboard              [][]KeyboardButton `json:"keyboard"`
+	IsPersistent          bool               `json:"is_persistent,omitempty"`
+	ResizeKeyboard        bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard       bool               `json:"one_time_keyboard,omitempty"`
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+	Selective             bool               `json:"selective,omitempty"`
+}
+
+// KeyboardButton represents one button of the reply keyboard
+type KeyboardButton struct {
+	Text            string      `json:"text"`
+	RequestContact  bool        `json:"request_contact,omitempty"`
+	RequestLocation bool        `json:"request_location,omitempty"`
+	WebApp          *WebAppInfo `json:"web_app,omitempty"`
+}
+
+// ReplyKeyboardRemove asks clients to remove the current custom keyboard
+type ReplyKeyboardRemove struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+	Selective      bool `json:"selective,omitempty"`
+}
+
+// ForceReply asks clients to display a reply interface to the user
+type ForceReply struct {
+	ForceReply            bool   `json:"force_reply"`
+	InputFieldPlaceholder string `json:"input_field_placeholder,omitempty"`
+	Selective             bool   `json:"selective,omitempty"`
+}
+
+// WebAppInfo describes a Web App
+type WebAppInfo struct {
+	URL string `json:"url"`
+}
+
+func (*InlineKeyboardMarkup) replyMarkup() {}
+func (*ReplyKeyboardMarkup) replyMarkup()  {}
+func (*ReplyKeyboardRemove) replyMarkup()  {}
+func (*ForceReply) replyMarkup()           {}
@@ -0,0 +1,175 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const (
+	// SecretTokenHeader carries the secret token set with WithSecretToken on every webhook request
+	SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	maxWebhookBodySize = 1 << 20 // 单个 Update 的最大请求体
+	webhookWorkers     = 16      // 同时处理 webhook 更新的 worker 数
+	webhookQueueSize   = 64      // 每个 worker 等待处理的更新数
+)
+
+// WebhookOption sets an optional setWebhook parameter
+type WebhookOption func(params map[string]interface{})
+
+// WithSecretToken makes Telegram send token in the X-Telegram-Bot-Api-Secret-Token header of every
+// webhook request, 1-256 characters of A-Z, a-z, 0-9, _ and -
+func WithSecretToken(token string) WebhookOption {
+	return func(params map[string]interface{}) {
+		params["secret_token"] = token
+	}
+}
+
+// WithAllowedUpdates limits the update types delivered, such as UpdateMessage and UpdateCallbackQuery
+func WithAllowedUpdates(updates ...string) WebhookOption {
+	return func(params map[string]interface{}) {
+		// 空列表表示接收除 chat_member 等以外的所有更新，需显式发送
+		if updates == nil {
+			updates = []string{}
+		}
+		params["allowed_updates"] = updates
+	}
+}
+
+// WithMaxConnections limits simultaneous webhook connections, 1-100
+func WithMaxConnections(n int) WebhookOption {
+	return func(params map[string]interface{}) {
+		params["max_connections"] = n
+	}
+}
+
+// WithIPAddress sends webhook requests to ip instead of resolving the webhook host through DNS
+func WithIPAddress(ip string) WebhookOption {
+	return func(params map[string]interface{}) {
+		params["ip_address"] = ip
+	}
+}
+
+// WithCertificate uploads the public key certificate of a self-signed webhook server
+func WithCertificate(certificate *InputFile) WebhookOption {
+	return func(params map[string]interface{}) {
+		params["certificate"] = certificate
+	}
+}
+
+// WithDropPendingUpdates discards updates waiting to be delivered
+func WithDropPendingUpdates() WebhookOption {
+	return func(params map[string]interface{}) {
+		params["drop_pending_updates"] = true
+	}
+}
+
+// webhookOptions 根据配置生成 setWebhook 的可选参数
+func (config *Config) webhookOptions() []WebhookOption {
+	var opts []WebhookOption
+	if config.WebhookSecret != "" {
+		opts = append(opts, WithSecretToken(config.WebhookSecret))
+	}
+	if len(config.AllowedUpdates) > 0 {
+		opts = append(opts, WithAllowedUpdates(config.AllowedUpdates...))
+	}
+	if config.WebhookMaxConnections > 0 {
+		opts = append(opts, WithMaxConnections(config.WebhookMaxConnections))
+	}
+	if config.WebhookIPAddress != "" {
+		opts = append(opts, WithIPAddress(config.WebhookIPAddress))
+	}
+	if config.WebhookCertificate != nil {
+		opts = append(opts, WithCertificate(config.WebhookCertificate))
+	}
+	if config.DropPendingUpdates {
+		opts = append(opts, WithDropPendingUpdates())
+	}
+	return opts
+}
+
+// WebhookHandler returns the http.Handler receiving webhook updates for the bot registered under alias.
+// It checks the secret token, decodes the Update and answers 200 once the update is queued; the update is
+// handled in the background so failing handlers do not make Telegram redeliver it. Updates of the same chat
+// are handled one at a time in arrival order, at most 16 updates run at once, and when all workers are
+// busy the request waits so Telegram slows down or redelivers.
+func WebhookHandler(alias string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 机器人可能晚于 Handler 注册，每次请求时查找
+		bot := newBotUsing(alias)
+		if bot == nil || bot.client == nil {
+			http.Error(w, "bot not registered", http.StatusServiceUnavailable)
+			return
+		}
+		bot.client.serveWebhook(w, r)
+	})
+}
+
+func (b *botClient) serveWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 常量时间比较，避免通过响应时间猜测密钥
+	if b.webhookSecret != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get(SecretTokenHeader)), []byte(b.webhookSecret)) != 1 {
+		b.log.Warn("[TelegramBot.Webhook] invalid secret token", "remote_addr", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update Update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&update); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		b.log.Warn("[TelegramBot.Webhook] malformed update", "error", err)
+		http.Error(w, "malformed update", http.StatusBadRequest)
+		return
+	}
+	// 请求结束后继续处理，保留 ctx 中的值但不随请求取消
+	if !b.dispatchUpdate(r.Context(), context.WithoutCancel(r.Context()), &update) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// updateJob 是等待 webhook worker 处理的一条更新
+type updateJob struct {
+	ctx    context.Context
+	update *Update
+}
+
+// dispatchUpdate 按聊天将更新交给固定的 worker，同一聊天的更新按到达顺序依次处理。
+// worker 队列已满时等待，wait 结束（Telegram 断开请求）时返回 false
+func (b *botClient) dispatchUpdate(wait, ctx context.Context, update *Update) bool {
+	b.dispatchOnce.Do(func() {
+		b.dispatchQueues = make([]chan updateJob, webhookWorkers)
+		for i := range b.dispatchQueues {
+			queue := make(chan updateJob, webhookQueueSize)
+			b.dispatchQueues[i] = queue
+			go func() {
+				for job := range queue {
+					b.handleUpdate(job.ctx, job.update)
+				}
+			}()
+		}
+	})
+
+	queue := b.dispatchQueues[uint64(updateChatKey(update))%webhookWorkers]
+	select {
+	case queue <- updateJob{ctx: ctx, update: update}:
+		return true
+	case <-wait.Done():
+		b.log.Warn("[TelegramBot.Webhook] workers busy, update left for redelivery", "update_id", update.UpdateID)
+		return false
+	}
+}